@@ -21,18 +21,29 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/goccy/go-yaml"
+	"github.com/k1LoW/errors"
 	"github.com/k1LoW/tbls/cmdutil"
 	"github.com/k1LoW/tbls/config"
 	"github.com/k1LoW/tbls/datasource"
 	"github.com/k1LoW/tbls/output/md"
 	"github.com/k1LoW/tbls/schema"
+	"github.com/k1LoW/tbls/schema/diff"
 	"github.com/spf13/cobra"
 )
 
+var (
+	diffFormat string
+	diffFailOn string
+)
+
 // diffCmd represents the diff command
 var diffCmd = &cobra.Command{
 	Use:   "diff [DSN] [DSN_OR_DOC_PATH]",
@@ -47,6 +58,15 @@ var diffCmd = &cobra.Command{
 			return nil
 		}
 
+		var failOn diff.Severity
+		if diffFailOn != "" {
+			sev, err := diff.ParseSeverity(diffFailOn)
+			if err != nil {
+				return err
+			}
+			failOn = sev
+		}
+
 		c, err := config.New()
 		if err != nil {
 			return err
@@ -134,6 +154,30 @@ var diffCmd = &cobra.Command{
 			}
 		}
 
+		if diffFormat != "" || diffFailOn != "" {
+			format := diffFormat
+			if format == "" {
+				format = "md"
+			}
+			d, err := compareSchemas(s, s2, c, c2, docPath)
+			if err != nil {
+				return err
+			}
+			if err := outputDiff(os.Stdout, d, format); err != nil {
+				return err
+			}
+			if diffFailOn == "" {
+				if d.HasChanges() {
+					os.Exit(1)
+				}
+				return nil
+			}
+			if d.HasChangesAtLeast(failOn) {
+				os.Exit(1)
+			}
+			return nil
+		}
+
 		switch {
 		case docPath != "":
 			diff, err = md.DiffSchemaAndDocs(docPath, s, c)
@@ -154,6 +198,60 @@ var diffCmd = &cobra.Command{
 	},
 }
 
+// compareSchemas compares schemas object by object.
+// When comparing with documents, schema.json in the document path is used.
+func compareSchemas(s, s2 *schema.Schema, c, c2 *config.Config, docPath string) (*diff.Diff, error) {
+	mdsn, err := c.MaskedDSN()
+	if err != nil {
+		return nil, err
+	}
+	if s2 != nil {
+		mdsn2, err := c2.MaskedDSN()
+		if err != nil {
+			return nil, err
+		}
+		d := diff.Compare(s, s2)
+		d.From = mdsn
+		d.To = mdsn2
+		return d, nil
+	}
+	if docPath == "" {
+		docPath = c.DocPath
+	}
+	sf := filepath.Join(docPath, config.SchemaFileName)
+	ds, err := datasource.AnalyzeJSONStringOrFile(sf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", sf, err)
+	}
+	d := diff.Compare(ds, s)
+	d.From = sf
+	d.To = mdsn
+	return d, nil
+}
+
+func outputDiff(wr io.Writer, d *diff.Diff, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(wr)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(d); err != nil {
+			return errors.WithStack(err)
+		}
+	case "yaml":
+		encoder := yaml.NewEncoder(wr)
+		if err := encoder.Encode(d); err != nil {
+			return errors.WithStack(err)
+		}
+	case "md":
+		if err := d.WriteMarkdown(wr); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported format '%s'", format)
+	}
+	return nil
+}
+
 func loadDiffOpts() []config.Option {
 	options := []config.Option{}
 	if adjust {
@@ -173,6 +271,8 @@ func init() {
 	diffCmd.Flags().StringVarP(&erFormat, "er-format", "t", "", fmt.Sprintf("ER diagrams output format (png, svg, jpg, ...). default: %s", config.DefaultERFormat))
 	diffCmd.Flags().BoolVarP(&adjust, "adjust-table", "j", false, "adjust column width of table")
 	diffCmd.Flags().StringVarP(&when, "when", "", "", "command execute condition")
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "", "", "structured diff output format (json, yaml, md)")
+	diffCmd.Flags().StringVarP(&diffFailOn, "fail-on", "", "", "exit with non-zero status only when there are changes of the severity or higher (breaking, additive, none)")
 	if err := diffCmd.MarkZshCompPositionalArgumentFile(2); err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "%s\n", err)
 		os.Exit(1)
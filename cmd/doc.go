@@ -21,16 +21,24 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/k1LoW/errors"
 	"github.com/k1LoW/tbls/cmdutil"
 	"github.com/k1LoW/tbls/config"
 	"github.com/k1LoW/tbls/datasource"
+	"github.com/k1LoW/tbls/output"
 	"github.com/k1LoW/tbls/output/gviz"
+	"github.com/k1LoW/tbls/output/html"
 	"github.com/k1LoW/tbls/output/json"
 	"github.com/k1LoW/tbls/output/md"
 	"github.com/k1LoW/tbls/schema"
@@ -40,13 +48,22 @@ import (
 var (
 	withoutER bool
 	rmDist    bool
+	docFormat string
+	docWatch  bool
+	docJobs   int
+	docIncr   bool
 )
 
+// docWatchInterval is the interval to check changes of files for `tbls doc --watch`
+const docWatchInterval = time.Second
+
+var supportDocFormats = []string{"md", "html"}
+
 // docCmd represents the doc command
 var docCmd = &cobra.Command{
 	Use:   "doc [DSN] [DOC_PATH]",
 	Short: "document a database",
-	Long:  `'tbls doc' analyzes a database and generate document in GitHub Friendly Markdown format.`,
+	Long:  `'tbls doc' analyzes a database and generate document in GitHub Friendly Markdown format or static HTML.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if allow, err := cmdutil.IsAllowedToExecute(when); !allow || err != nil {
 			if err != nil {
@@ -55,9 +72,8 @@ var docCmd = &cobra.Command{
 			return nil
 		}
 
-		c, err := config.New()
-		if err != nil {
-			return err
+		if !slices.Contains(supportDocFormats, docFormat) {
+			return fmt.Errorf("unsupported doc format: %s", docFormat)
 		}
 
 		options, err := loadDocArgs(args)
@@ -65,67 +81,144 @@ var docCmd = &cobra.Command{
 			return err
 		}
 
-		if err := c.Load(configPath, options...); err != nil {
+		c, err := generateDoc(options, force, rmDist)
+		// do not start watching to overwrite the existing document without --force
+		if !docWatch || c == nil || errors.Is(err, output.ErrAlreadyExists) {
 			return err
 		}
-
-		s, err := datasource.Analyze(c.DSN)
 		if err != nil {
-			return err
+			printError(err)
 		}
 
-		if err := c.ModifySchema(s); err != nil {
-			return err
-		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		watchDoc(ctx, c, options)
+		return nil
+	},
+}
 
-		if rmDist && c.DocPath != "" {
-			if _, err := os.Lstat(c.DocPath); err == nil {
-				docs, err := os.ReadDir(c.DocPath)
-				if err != nil {
-					return errors.WithStack(err)
-				}
-				for _, f := range docs {
-					if err := os.RemoveAll(filepath.Join(c.DocPath, f.Name())); err != nil {
-						return errors.WithStack(err)
-					}
+// generateDoc analyze the database and generate the document. It returns the loaded config even if generation fails.
+func generateDoc(options []config.Option, force, rmDist bool) (*config.Config, error) {
+	c, err := config.New()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Load(configPath, options...); err != nil {
+		return nil, err
+	}
+
+	s, err := datasource.Analyze(c.DSN)
+	if err != nil {
+		return c, err
+	}
+
+	if err := c.ModifySchema(s); err != nil {
+		return c, err
+	}
+
+	if rmDist && c.DocPath != "" {
+		if _, err := os.Lstat(c.DocPath); err == nil {
+			docs, err := os.ReadDir(c.DocPath)
+			if err != nil {
+				return c, errors.WithStack(err)
+			}
+			for _, f := range docs {
+				if err := os.RemoveAll(filepath.Join(c.DocPath, f.Name())); err != nil {
+					return c, errors.WithStack(err)
 				}
 			}
 		}
+	}
 
+	switch docFormat {
+	case "html":
+		// ER diagrams are embedded in HTML as inline SVG
+		if err := html.Output(s, c, force); err != nil {
+			return c, err
+		}
+	default:
 		if c.NeedToGenerateERImages() {
 			if err := gviz.Output(s, c, force); err != nil {
-				return err
+				return c, err
 			}
 		}
 
 		if err := md.Output(s, c, force); err != nil {
-			return err
+			return c, err
 		}
+	}
 
-		// output schema.json
-		if !c.DisableOutputSchema {
-			if err := withSchemaFile(s, c); err != nil {
-				return err
+	// output schema.json
+	if !c.DisableOutputSchema {
+		if err := withSchemaFile(s, c); err != nil {
+			return c, err
+		}
+		// output content hashes of tables for incremental generation
+		if err := output.WriteHashes(s, c); err != nil {
+			return c, err
+		}
+	}
+
+	return c, nil
+}
+
+// watchDoc regenerate the document when the config file, the template files or the source files change until ctx is done
+func watchDoc(ctx context.Context, c *config.Config, options []config.Option) {
+	w := cmdutil.NewWatcher(docWatchFiles(c))
+	fmt.Println("Watching for changes...")
+	ticker := time.NewTicker(docWatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed := w.Changed()
+			if len(changed) == 0 {
+				continue
+			}
+			fmt.Printf("Changed: %s\n", strings.Join(changed, ", "))
+			// regenerate only the documents of changed tables. without schema.hash.json, rebuild the document generated above
+			nc, err := generateDoc(append(slices.Clone(options), config.Incremental(true)), c.DisableOutputSchema, false)
+			if err != nil {
+				printError(err)
+			}
+			if nc != nil {
+				c = nc
 			}
+			// the files to watch may change by the config
+			w = cmdutil.NewWatcher(docWatchFiles(c))
 		}
+	}
+}
 
-		return nil
-	},
+// docWatchFiles return the files to watch for `tbls doc --watch`
+func docWatchFiles(c *config.Config) []string {
+	files := []string{}
+	if c.Path != "" {
+		files = append(files, c.Path)
+	}
+	files = append(files, c.Templates.Files()...)
+	if sf, err := datasource.SourceFiles(c.DSN); err == nil {
+		files = append(files, sf...)
+	}
+	return files
 }
 
-func withSchemaFile(s *schema.Schema, c *config.Config) (e error) {
-	sf, err := os.Create(c.SchemaFilePath())
-	if err != nil {
+func withSchemaFile(s *schema.Schema, c *config.Config) error {
+	buf := new(bytes.Buffer)
+	j := json.New(true)
+	if err := j.OutputSchema(buf, s); err != nil {
 		return err
 	}
-	defer func() {
-		_ = sf.Close()
-	}()
-	fmt.Printf("%s\n", c.SchemaFilePath())
-	j := json.New(true)
-	if err := j.OutputSchema(sf, s); err != nil {
+	written, err := output.WriteFileIfChanged(c.SchemaFilePath(), buf.Bytes())
+	if err != nil {
 		return err
 	}
+	if written {
+		fmt.Printf("%s\n", c.SchemaFilePath())
+	}
 	return nil
 }
 
@@ -148,6 +241,14 @@ func loadDocArgs(args []string) ([]config.Option, error) {
 	options = append(options, config.Include(append(tables, includes...)))
 	options = append(options, config.Exclude(excludes))
 	options = append(options, config.IncludeLabels(labels))
+	options = append(options, config.Jobs(docJobs))
+	if docJobs > 1 {
+		// render ER diagrams in subprocesses of tbls itself to run them in parallel
+		if exe, err := os.Executable(); err == nil {
+			options = append(options, config.ERRenderCommand([]string{exe, "render-er"}))
+		}
+	}
+	options = append(options, config.Incremental(docIncr))
 	if len(args) == 2 {
 		options = append(options, config.DSNURL(args[0]))
 		options = append(options, config.DocPath(args[1]))
@@ -173,6 +274,10 @@ func init() {
 	docCmd.Flags().StringVarP(&when, "when", "", "", "command execute condition")
 	docCmd.Flags().StringVarP(&baseUrl, "base-url", "b", "", "base url for links")
 	docCmd.Flags().BoolVarP(&rmDist, "rm-dist", "", false, "remove files in docPath before generating documents")
+	docCmd.Flags().BoolVarP(&docWatch, "watch", "w", false, "regenerate document when the config file, template files or source files ( json://, sql://, migrations://, dbml:// ) change")
+	docCmd.Flags().BoolVarP(&docIncr, "incremental", "", false, "regenerate only the documents of tables changed since the last generation")
+	docCmd.Flags().IntVarP(&docJobs, "jobs", "", 1, "number of parallel jobs to render ER diagrams and documents")
+	docCmd.Flags().StringVarP(&docFormat, "format", "", "md", fmt.Sprintf("document format (%s)", strings.Join(supportDocFormats, ", ")))
 	docCmd.Flags().StringSliceVarP(&tables, "table", "", []string{}, "target table (tables to include)")
 	docCmd.Flags().StringSliceVarP(&includes, "include", "", []string{}, "tables to include")
 	docCmd.Flags().StringSliceVarP(&excludes, "exclude", "", []string{}, "tables to exclude")
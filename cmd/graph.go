@@ -0,0 +1,162 @@
+/*
+Copyright © 2020 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/cmdutil"
+	"github.com/k1LoW/tbls/config"
+	"github.com/k1LoW/tbls/schema"
+	"github.com/spf13/cobra"
+)
+
+var (
+	graphFormat  string
+	graphReverse bool
+)
+
+// graphCmd represents the graph command
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "analyze the graph of tables linked by relations",
+	Long:  `'tbls graph' analyze the graph of tables linked by relations.`,
+}
+
+// graphOrderCmd represents the graph order command
+var graphOrderCmd = &cobra.Command{
+	Use:   "order [DSN]",
+	Short: "list tables in topological order",
+	Long: `'tbls graph order' list tables so that parent tables come before child tables ( safe load order ).
+
+With --reverse, child tables come before parent tables ( safe truncate order ).
+Tables in a cycle are listed together.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := loadGraphSchema(args)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			return nil
+		}
+		tables := s.TopologicalOrder()
+		if graphReverse {
+			slices.Reverse(tables)
+		}
+		names := []string{}
+		for _, t := range tables {
+			names = append(names, t.Name)
+		}
+		if graphFormat == "json" {
+			return outputGraphJSON(names)
+		}
+		for _, n := range names {
+			fmt.Println(n)
+		}
+		if cycles := s.Cycles(); len(cycles) > 0 {
+			_, _ = fmt.Fprintf(os.Stderr, "%d cycles detected. see 'tbls graph cycles'\n", len(cycles))
+		}
+		return nil
+	},
+}
+
+// graphCyclesCmd represents the graph cycles command
+var graphCyclesCmd = &cobra.Command{
+	Use:   "cycles [DSN]",
+	Short: "list tables that reference each other cyclically",
+	Long:  `'tbls graph cycles' list groups of tables that reference each other cyclically ( strongly connected components ).`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := loadGraphSchema(args)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			return nil
+		}
+		cycles := [][]string{}
+		for _, cycle := range s.Cycles() {
+			names := []string{}
+			for _, t := range cycle {
+				names = append(names, t.Name)
+			}
+			cycles = append(cycles, names)
+		}
+		if graphFormat == "json" {
+			return outputGraphJSON(cycles)
+		}
+		for _, names := range cycles {
+			fmt.Println(strings.Join(names, ", "))
+		}
+		return nil
+	},
+}
+
+// loadGraphSchema return nil when the command is not allowed to execute
+func loadGraphSchema(args []string) (*schema.Schema, error) {
+	if allow, err := cmdutil.IsAllowedToExecute(when); !allow || err != nil {
+		return nil, err
+	}
+	if graphFormat != "" && graphFormat != "json" {
+		return nil, fmt.Errorf("unsupported format: %s", graphFormat)
+	}
+	c, err := config.New()
+	if err != nil {
+		return nil, err
+	}
+	options := []config.Option{}
+	if len(args) == 1 {
+		options = append(options, config.DSNURL(args[0]))
+	}
+	if dsn != "" {
+		options = append(options, config.DSNURL(dsn))
+	}
+	if err := c.Load(configPath, options...); err != nil {
+		return nil, err
+	}
+	return getSchemaFromJSONorDSN(c)
+}
+
+func outputGraphJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(graphCmd)
+	graphCmd.AddCommand(graphOrderCmd, graphCyclesCmd)
+	graphCmd.PersistentFlags().StringVarP(&dsn, "dsn", "", "", "data source name")
+	graphCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "config file path")
+	graphCmd.PersistentFlags().StringVarP(&graphFormat, "format", "t", "", "output format (json)")
+	graphCmd.PersistentFlags().StringVarP(&when, "when", "", "", "command execute condition")
+	graphOrderCmd.Flags().BoolVarP(&graphReverse, "reverse", "", false, "child tables come before parent tables ( safe truncate order )")
+}
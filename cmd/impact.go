@@ -0,0 +1,151 @@
+/*
+Copyright © 2020 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/cmdutil"
+	"github.com/k1LoW/tbls/config"
+	"github.com/k1LoW/tbls/schema"
+	"github.com/labstack/gommon/color"
+	"github.com/spf13/cobra"
+)
+
+var impactFormat string
+
+// impactCmd represents the impact command
+var impactCmd = &cobra.Command{
+	Use:   "impact [TABLE[.COLUMN]] [DSN]",
+	Short: "list everything that depends on the table or column",
+	Long: `'tbls impact' list everything that depends on the table or column.
+
+It lists child relations ( transitively, with distance ), views referencing the table, triggers on the table, indexes and constraints covering the column, and viewpoints containing the table.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if allow, err := cmdutil.IsAllowedToExecute(when); !allow || err != nil {
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+		if impactFormat != "" && impactFormat != "json" {
+			return fmt.Errorf("unsupported format: %s", impactFormat)
+		}
+
+		c, err := config.New()
+		if err != nil {
+			return err
+		}
+		options := []config.Option{}
+		if len(args) == 2 {
+			options = append(options, config.DSNURL(args[1]))
+		}
+		if dsn != "" {
+			options = append(options, config.DSNURL(dsn))
+		}
+		if err := c.Load(configPath, options...); err != nil {
+			return err
+		}
+
+		s, err := getSchemaFromJSONorDSN(c)
+		if err != nil {
+			return err
+		}
+		t, col, err := findImpactTarget(s, args[0])
+		if err != nil {
+			return err
+		}
+		i := s.Impact(t, col)
+
+		if impactFormat == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(i.ToJSONObject()); err != nil {
+				return errors.WithStack(err)
+			}
+			return nil
+		}
+
+		j := i.ToJSONObject()
+		fmt.Println(color.White(j.Target, color.B))
+		printImpactSection("Relations", len(i.Relations), func() {
+			for _, r := range i.Relations {
+				fmt.Printf("  %d  %s\n", r.Distance, r.String())
+			}
+		})
+		for _, sec := range []struct {
+			name  string
+			names []string
+		}{
+			{"Views", j.Views},
+			{"Triggers", j.Triggers},
+			{"Indexes", j.Indexes},
+			{"Constraints", j.Constraints},
+			{"Viewpoints", j.Viewpoints},
+		} {
+			printImpactSection(sec.name, len(sec.names), func() {
+				for _, n := range sec.names {
+					fmt.Printf("  %s\n", n)
+				}
+			})
+		}
+		return nil
+	},
+}
+
+func printImpactSection(name string, n int, printItems func()) {
+	fmt.Printf("\n%s\n", color.White(fmt.Sprintf("%s (%d)", name, n), color.B))
+	printItems()
+}
+
+// findImpactTarget find the table by TABLE or the column by TABLE.COLUMN. A table name takes precedence.
+func findImpactTarget(s *schema.Schema, target string) (*schema.Table, *schema.Column, error) {
+	if t, err := s.FindTableByName(target); err == nil {
+		return t, nil, nil
+	}
+	i := strings.LastIndex(target, ".")
+	if i < 0 {
+		return nil, nil, fmt.Errorf("not found table '%s'", target)
+	}
+	t, err := s.FindTableByName(target[:i])
+	if err != nil {
+		return nil, nil, fmt.Errorf("not found table or column '%s'", target)
+	}
+	c, err := t.FindColumnByName(target[i+1:])
+	if err != nil {
+		return nil, nil, err
+	}
+	return t, c, nil
+}
+
+func init() {
+	rootCmd.AddCommand(impactCmd)
+	impactCmd.Flags().StringVarP(&dsn, "dsn", "", "", "data source name")
+	impactCmd.Flags().StringVarP(&configPath, "config", "c", "", "config file path")
+	impactCmd.Flags().StringVarP(&impactFormat, "format", "t", "", "output format (json)")
+	impactCmd.Flags().StringVarP(&when, "when", "", "", "command execute condition")
+}
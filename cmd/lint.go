@@ -23,16 +23,25 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/k1LoW/errors"
 	"github.com/k1LoW/tbls/cmdutil"
 	"github.com/k1LoW/tbls/config"
 	"github.com/k1LoW/tbls/datasource"
+	"github.com/k1LoW/tbls/lint"
 	"github.com/labstack/gommon/color"
 	"github.com/spf13/cobra"
 )
 
+var (
+	lintFormat         string
+	lintFailOn         string
+	lintBaseline       string
+	lintUpdateBaseline bool
+)
+
 // lintCmd represents the lint command
 var lintCmd = &cobra.Command{
 	Use:   "lint [DSN] [DOC_PATH]",
@@ -46,6 +55,16 @@ var lintCmd = &cobra.Command{
 			return nil
 		}
 
+		if lintFormat != "" && !slices.Contains(lint.SupportFormats, lintFormat) {
+			return fmt.Errorf("unsupported format: %s", lintFormat)
+		}
+		if !slices.Contains(config.Severities, lintFailOn) {
+			return fmt.Errorf("unsupported severity: %s", lintFailOn)
+		}
+		if lintUpdateBaseline && lintBaseline == "" {
+			return errors.New("--update-baseline requires --baseline")
+		}
+
 		c, err := config.New()
 		if err != nil {
 			return err
@@ -69,23 +88,62 @@ var lintCmd = &cobra.Command{
 			return err
 		}
 
-		l := reflect.Indirect(reflect.ValueOf(c.Lint))
-		t := l.Type()
+		ruleWarns, err := c.Lint.Check(s, s.NormalizeTableNames(c.LintExclude))
+		if err != nil {
+			return err
+		}
+
+		suppressed := 0
+		if lintBaseline != "" {
+			if lintUpdateBaseline {
+				b := lint.NewBaseline(ruleWarns)
+				if err := b.Write(lintBaseline); err != nil {
+					return err
+				}
+				fmt.Printf("%s (%d warns)\n", lintBaseline, len(b.Warns))
+				return nil
+			}
+			b, err := lint.ReadBaseline(lintBaseline)
+			if err != nil {
+				return fmt.Errorf("failed to read baseline (create it with --update-baseline): %w", err)
+			}
+			filtered := b.Filter(ruleWarns)
+			suppressed = len(ruleWarns) - len(filtered)
+			ruleWarns = filtered
+		}
+
+		failed := slices.ContainsFunc(ruleWarns, func(w config.RuleWarn) bool {
+			return w.IsAtLeast(lintFailOn)
+		})
 
-		ruleWarns := []config.RuleWarn{}
-		for i := 0; i < t.NumField(); i++ {
-			r := l.Field(i)
-			v, ok := r.Interface().(config.Rule)
-			if !ok {
-				return fmt.Errorf("invalid rule: %v", r.Interface())
+		if lintFormat != "" {
+			warns, err := lint.NewWarns(c, ruleWarns)
+			if err != nil {
+				return err
+			}
+			if err := lint.Output(os.Stdout, lintFormat, warns); err != nil {
+				return err
+			}
+			if failed {
+				os.Exit(1)
 			}
-			ruleWarns = append(ruleWarns, v.Check(s, s.NormalizeTableNames(c.LintExclude))...)
+			return nil
 		}
+
 		if len(ruleWarns) > 0 {
 			for _, warn := range ruleWarns {
-				fmt.Printf("%s%s\n", color.Cyan(warn.Target), color.White(fmt.Sprintf(": %s", warn.Message), color.B))
+				msg := fmt.Sprintf(": %s", warn.Message)
+				if warn.Severity != config.SeverityError {
+					msg = fmt.Sprintf("%s (%s)", msg, warn.Severity)
+				}
+				fmt.Printf("%s%s\n", color.Cyan(warn.Target), color.White(msg, color.B))
 			}
 			fmt.Println(color.White(fmt.Sprintf("\n%d detected", len(ruleWarns)), color.B))
+		}
+		if suppressed > 0 {
+			fmt.Printf("%d suppressed by baseline\n", suppressed)
+		}
+		if failed {
 			os.Exit(1)
 		}
 
@@ -116,6 +174,10 @@ func init() {
 	lintCmd.Flags().StringVarP(&dsn, "dsn", "", "", "data source name")
 	lintCmd.Flags().StringVarP(&configPath, "config", "c", "", "config file path")
 	lintCmd.Flags().StringVarP(&when, "when", "", "", "command execute condition")
+	lintCmd.Flags().StringVarP(&lintFormat, "format", "t", "", fmt.Sprintf("output format (%s)", strings.Join(lint.SupportFormats, ", ")))
+	lintCmd.Flags().StringVarP(&lintBaseline, "baseline", "", "", "baseline file path. warns recorded in the baseline are suppressed")
+	lintCmd.Flags().BoolVarP(&lintUpdateBaseline, "update-baseline", "", false, "record current warns to the baseline file")
+	lintCmd.Flags().StringVarP(&lintFailOn, "fail-on", "", config.SeverityError, fmt.Sprintf("exit with non-zero status when warns of the severity or higher are detected (%s)", strings.Join(config.Severities, ", ")))
 	err := lintCmd.MarkZshCompPositionalArgumentFile(2)
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "%s\n", err)
@@ -0,0 +1,149 @@
+/*
+Copyright © 2020 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/cmdutil"
+	"github.com/k1LoW/tbls/config"
+	"github.com/k1LoW/tbls/datasource"
+	"github.com/k1LoW/tbls/migrate"
+	"github.com/k1LoW/tbls/schema"
+	"github.com/spf13/cobra"
+)
+
+var dialect string
+
+// migratePlanCmd represents the migrate-plan command
+var migratePlanCmd = &cobra.Command{
+	Use:   "migrate-plan [FROM_DSN] [TO_DSN]",
+	Short: "generate SQL that migrates database schema to ( document or other database ) schema",
+	Long: `'tbls migrate-plan' generates SQL that migrates database schema to ( schema.json of generated document or other database schema ).
+
+If TO_DSN is not specified, schema.json of generated document is used as the target schema.`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if allow, err := cmdutil.IsAllowedToExecute(when); !allow || err != nil {
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+
+		c, err := config.New()
+		if err != nil {
+			return err
+		}
+		options, err := loadMigratePlanArgs(args)
+		if err != nil {
+			return err
+		}
+		if err := c.Load(configPath, options...); err != nil {
+			return err
+		}
+
+		from, err := datasource.Analyze(c.DSN)
+		if err != nil {
+			return err
+		}
+		if err := c.ModifySchema(from); err != nil {
+			return err
+		}
+
+		var to *schema.Schema
+		if len(args) == 2 {
+			c2, err := config.New()
+			if err != nil {
+				return err
+			}
+			if err := c2.Load(configPath, config.DSNURL(args[1])); err != nil {
+				return err
+			}
+			to, err = datasource.Analyze(c2.DSN)
+			if err != nil {
+				return err
+			}
+			if err := c2.ModifySchema(to); err != nil {
+				return err
+			}
+		} else {
+			// falling back to the DSN would diff the database against itself
+			if _, err := os.Stat(c.SchemaFilePath()); err != nil {
+				return fmt.Errorf("no target schema: %s does not exist. please generate the document or specify TO_DSN", c.SchemaFilePath())
+			}
+			to, err = getSchemaFromJSONorDSN(c)
+			if err != nil {
+				return err
+			}
+		}
+
+		d := dialect
+		if d == "" {
+			if from.Driver == nil {
+				return errors.New("can not detect dialect. please specify --dialect")
+			}
+			d = from.Driver.Name
+		}
+		md, err := migrate.ParseDialect(d)
+		if err != nil {
+			return err
+		}
+
+		stmts, err := migrate.Plan(from, to, md)
+		if err != nil {
+			return err
+		}
+		for _, stmt := range stmts {
+			fmt.Println(stmt)
+		}
+		return nil
+	},
+}
+
+func loadMigratePlanArgs(args []string) ([]config.Option, error) {
+	options := []config.Option{}
+	if len(args) > 2 {
+		return options, errors.WithStack(errors.New("too many arguments"))
+	}
+	if len(args) > 0 {
+		options = append(options, config.DSNURL(args[0]))
+	}
+	if dsn != "" {
+		options = append(options, config.DSNURL(dsn))
+	}
+	return options, nil
+}
+
+func init() {
+	rootCmd.AddCommand(migratePlanCmd)
+	migratePlanCmd.Flags().StringVarP(&dsn, "dsn", "", "", "data source name")
+	migratePlanCmd.Flags().StringVarP(&configPath, "config", "c", "", "config file path")
+	migratePlanCmd.Flags().StringVarP(&dialect, "dialect", "", "", "SQL dialect (postgres, mysql, sqlite). default: dialect of the driver of FROM_DSN")
+	migratePlanCmd.Flags().StringVarP(&when, "when", "", "", "command execute condition")
+	if err := migratePlanCmd.MarkZshCompPositionalArgumentFile(2); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+}
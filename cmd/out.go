@@ -30,6 +30,7 @@ import (
 	"github.com/k1LoW/tbls/config"
 	"github.com/k1LoW/tbls/output"
 	tbls_config "github.com/k1LoW/tbls/output/config"
+	"github.com/k1LoW/tbls/output/dbml"
 	"github.com/k1LoW/tbls/output/dot"
 	"github.com/k1LoW/tbls/output/gviz"
 	"github.com/k1LoW/tbls/output/json"
@@ -97,6 +98,8 @@ var outCmd = &cobra.Command{
 			o = plantuml.New(c)
 		case "mermaid":
 			o = mermaid.New(c)
+		case "dbml":
+			o = dbml.New(c)
 		case "png", "svg", "jpg":
 			c.ER.Format = format
 			o = gviz.New(c)
@@ -0,0 +1,155 @@
+/*
+Copyright © 2020 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/cmdutil"
+	"github.com/k1LoW/tbls/config"
+	"github.com/k1LoW/tbls/output/gviz"
+	"github.com/k1LoW/tbls/schema"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pathSQL   bool
+	pathLimit int
+	pathER    string
+)
+
+// pathCmd represents the path command
+var pathCmd = &cobra.Command{
+	Use:   "path [TABLE_A] [TABLE_B] [DSN]",
+	Short: "show the shortest join paths between two tables",
+	Long: `'tbls path' show the shortest join paths between two tables through relations.
+
+Relations are followed in both directions ( child to parent and parent to child ).`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if allow, err := cmdutil.IsAllowedToExecute(when); !allow || err != nil {
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+
+		c, err := config.New()
+		if err != nil {
+			return err
+		}
+		options := []config.Option{}
+		if len(args) == 3 {
+			options = append(options, config.DSNURL(args[2]))
+		}
+		if dsn != "" {
+			options = append(options, config.DSNURL(dsn))
+		}
+		if err := c.Load(configPath, options...); err != nil {
+			return err
+		}
+
+		s, err := getSchemaFromJSONorDSN(c)
+		if err != nil {
+			return err
+		}
+		from, err := s.FindTableByName(args[0])
+		if err != nil {
+			return err
+		}
+		to, err := s.FindTableByName(args[1])
+		if err != nil {
+			return err
+		}
+		if from == to {
+			return fmt.Errorf("'%s' and '%s' are the same table", args[0], args[1])
+		}
+
+		paths := s.ShortestJoinPaths(from, to, pathLimit)
+		if len(paths) == 0 {
+			return fmt.Errorf("no path between '%s' and '%s'", from.Name, to.Name)
+		}
+		for i, p := range paths {
+			if i > 0 {
+				fmt.Println()
+			}
+			if pathSQL {
+				fmt.Println(p.SelectSQL())
+				continue
+			}
+			for _, st := range p {
+				fmt.Println(st.String())
+			}
+		}
+
+		if pathER != "" {
+			if err := outputPathER(c, s, paths, pathER); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// outputPathER output the ER diagram of the tables on the paths
+func outputPathER(c *config.Config, s *schema.Schema, paths []schema.JoinPath, path string) error {
+	include := []string{}
+	for _, p := range paths {
+		for _, t := range p.Tables() {
+			if !slices.Contains(include, t.Name) {
+				include = append(include, t.Name)
+			}
+		}
+	}
+	cs, err := s.Clone()
+	if err != nil {
+		return err
+	}
+	if err := cs.Filter(&schema.FilterOption{Include: include}); err != nil {
+		return err
+	}
+	erFormat := strings.TrimPrefix(filepath.Ext(path), ".")
+	if erFormat == "" {
+		erFormat = c.ER.Format
+	}
+	f, err := os.OpenFile(filepath.Clean(path), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+	return gviz.NewWithFormat(c, erFormat).OutputSchema(f, cs)
+}
+
+func init() {
+	rootCmd.AddCommand(pathCmd)
+	pathCmd.Flags().StringVarP(&dsn, "dsn", "", "", "data source name")
+	pathCmd.Flags().StringVarP(&configPath, "config", "c", "", "config file path")
+	pathCmd.Flags().BoolVarP(&pathSQL, "sql", "", false, "output the skeleton of SELECT statement")
+	pathCmd.Flags().IntVarP(&pathLimit, "limit", "", 10, "max number of paths. 0 means no limit")
+	pathCmd.Flags().StringVarP(&pathER, "er", "", "", "output the ER diagram of the tables on the paths to the file ( png, svg, jpg )")
+	pathCmd.Flags().StringVarP(&when, "when", "", "", "command execute condition")
+}
@@ -0,0 +1,63 @@
+// Copyright © 2018 Ken'ichiro Oyama <k1lowxb@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/config"
+	"github.com/k1LoW/tbls/output/gviz"
+	"github.com/spf13/cobra"
+)
+
+var (
+	renderERFormat string
+	renderERFont   string
+)
+
+// renderERCmd represents the render-er command
+var renderERCmd = &cobra.Command{
+	Use:    "render-er",
+	Short:  "render ER diagram image from DOT of stdin",
+	Long:   `'tbls render-er' renders ER diagram image from DOT of stdin to stdout. It is used by 'tbls doc --jobs' to render ER diagrams in parallel.`,
+	Args:   cobra.NoArgs,
+	Hidden: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		c, err := config.New()
+		if err != nil {
+			return err
+		}
+		c.ER.Font = renderERFont
+		return gviz.NewWithFormat(c, renderERFormat).RenderDOT(os.Stdout, b)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renderERCmd)
+	renderERCmd.Flags().StringVarP(&renderERFormat, "format", "", "png", "image format")
+	renderERCmd.Flags().StringVarP(&renderERFont, "font", "", "", "font name or path")
+}
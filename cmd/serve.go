@@ -0,0 +1,101 @@
+/*
+Copyright © 2020 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/cmdutil"
+	"github.com/k1LoW/tbls/config"
+	"github.com/k1LoW/tbls/server"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr     string
+	serveInterval time.Duration
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve [DSN]",
+	Short: "serve database document as HTML",
+	Long: `'tbls serve' analyzes a database and serves the document as HTML without generating files.
+
+The database is re-analyzed when the config file changes ( or on every --interval ), and opened pages are reloaded automatically.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if allow, err := cmdutil.IsAllowedToExecute(when); !allow || err != nil {
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+
+		options := []config.Option{}
+		if len(args) == 1 {
+			options = append(options, config.DSNURL(args[0]))
+		}
+		if dsn != "" {
+			options = append(options, config.DSNURL(dsn))
+		}
+		s := server.New(configPath, options, server.Interval(serveInterval))
+		if err := s.Load(); err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		go s.Watch(ctx, os.Stderr)
+
+		srv := &http.Server{
+			Addr:              serveAddr,
+			Handler:           s.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			<-ctx.Done()
+			_ = srv.Shutdown(context.Background())
+		}()
+		fmt.Printf("Serving at http://%s/\n", serveAddr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return errors.WithStack(err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVarP(&dsn, "dsn", "", "", "data source name")
+	serveCmd.Flags().StringVarP(&configPath, "config", "c", "", "config file path")
+	serveCmd.Flags().StringVarP(&serveAddr, "addr", "", "localhost:8080", "address to listen on")
+	serveCmd.Flags().DurationVarP(&serveInterval, "interval", "", 0, "interval to re-analyze the database ( e.g. 30s ). by default, the database is re-analyzed only when the config file changes")
+	serveCmd.Flags().StringVarP(&when, "when", "", "", "command execute condition")
+}
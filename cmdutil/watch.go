@@ -0,0 +1,49 @@
+package cmdutil
+
+import (
+	"os"
+	"sort"
+	"time"
+)
+
+// Watcher detects changes of files by polling the modification times and sizes
+type Watcher struct {
+	states map[string]fileState
+}
+
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// NewWatcher return Watcher that records the current states of the files
+func NewWatcher(paths []string) *Watcher {
+	w := &Watcher{states: map[string]fileState{}}
+	for _, p := range paths {
+		w.states[p] = stat(p)
+	}
+	return w
+}
+
+// Changed return the files changed since the last call ( or NewWatcher )
+func (w *Watcher) Changed() []string {
+	changed := []string{}
+	for p, prev := range w.states {
+		cur := stat(p)
+		if cur != prev {
+			changed = append(changed, p)
+			w.states[p] = cur
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func stat(path string) fileState {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, modTime: fi.ModTime(), size: fi.Size()}
+}
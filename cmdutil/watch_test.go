@@ -0,0 +1,51 @@
+package cmdutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.yml")
+	b := filepath.Join(dir, "b.sql")
+	if err := os.WriteFile(a, []byte("a"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	w := NewWatcher([]string{a, b})
+	if diff := cmp.Diff(w.Changed(), []string{}); diff != "" {
+		t.Error(diff)
+	}
+
+	// modified
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(a, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(w.Changed(), []string{a}); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(w.Changed(), []string{}); diff != "" {
+		t.Error(diff)
+	}
+
+	// created
+	if err := os.WriteFile(b, []byte("b"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(w.Changed(), []string{b}); diff != "" {
+		t.Error(diff)
+	}
+
+	// removed
+	if err := os.Remove(a); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(w.Changed(), []string{a}); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -29,6 +29,9 @@ var SupportERFormat = []string{"png", "jpg", "svg", "mermaid"}
 
 const SchemaFileName = "schema.json"
 
+// SchemaHashFileName is the file name of content hashes of tables for incremental generation
+const SchemaHashFileName = "schema.hash.json"
+
 // DefaultERDistance is the default distance between tables that display relations in the ER
 var DefaultERDistance = 1
 
@@ -61,6 +64,15 @@ type Config struct {
 	// Table labels to be included
 	includeLabels []string
 
+	// Number of parallel jobs to generate documents
+	jobs int
+
+	// Command to render ER diagrams in subprocesses
+	erRenderCommand []string
+
+	// Regenerate only the documents of changed tables
+	incremental bool
+
 	// Path of config file
 	Path string `yaml:"-"`
 	root string `yaml:"-"`
@@ -231,6 +243,33 @@ func IncludeLabels(l []string) Option {
 	}
 }
 
+// Jobs return Option set Config.jobs
+func Jobs(n int) Option {
+	return func(c *Config) error {
+		if n < 0 {
+			return fmt.Errorf("invalid number of jobs: %d", n)
+		}
+		c.jobs = n
+		return nil
+	}
+}
+
+// ERRenderCommand return Option set Config.erRenderCommand
+func ERRenderCommand(cmd []string) Option {
+	return func(c *Config) error {
+		c.erRenderCommand = cmd
+		return nil
+	}
+}
+
+// Incremental return Option set Config.incremental
+func Incremental(incremental bool) Option {
+	return func(c *Config) error {
+		c.incremental = incremental
+		return nil
+	}
+}
+
 // New return Config
 func New() (*Config, error) {
 	c := Config{}
@@ -581,6 +620,27 @@ func (c *Config) SchemaFilePath() string {
 	return filepath.Join(c.DocPath, SchemaFileName)
 }
 
+// SchemaHashFilePath return the path of content hashes of tables
+func (c *Config) SchemaHashFilePath() string {
+	return filepath.Join(c.DocPath, SchemaHashFileName)
+}
+
+// Jobs return the number of parallel jobs to generate documents. The default is 1.
+func (c *Config) Jobs() int {
+	return max(c.jobs, 1)
+}
+
+// ERRenderCommand return the command to render ER diagrams in subprocesses.
+// The command reads DOT from stdin and writes the image to stdout.
+func (c *Config) ERRenderCommand() []string {
+	return c.erRenderCommand
+}
+
+// Incremental return true if incremental generation is requested
+func (c *Config) Incremental() bool {
+	return c.incremental
+}
+
 func (c *Config) NeedToGenerateERImages() bool {
 	if c.ER.Skip {
 		return false
@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"reflect"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
@@ -24,12 +26,55 @@ type Lint struct {
 	RequireForeignKeyIndex   RequireForeignKeyIndex   `yaml:"requireForeignKeyIndex"`
 	LabelStyleBigQuery       LabelStyleBigQuery       `yaml:"labelStyleBigQuery"`
 	RequireViewpoints        RequireViewpoints        `yaml:"requireViewpoints"`
+	NamingConvention         NamingConvention         `yaml:"namingConvention"`
+	ForeignKeyTypeMismatch   ForeignKeyTypeMismatch   `yaml:"foreignKeyTypeMismatch"`
+	RedundantIndex           RedundantIndex           `yaml:"redundantIndex"`
+	RequirePrimaryKey        RequirePrimaryKey        `yaml:"requirePrimaryKey"`
+	NullableIdentifierColumn NullableIdentifierColumn `yaml:"nullableIdentifierColumn"`
+	ReservedKeyword          ReservedKeyword          `yaml:"reservedKeyword"`
+	CyclicRelations          CyclicRelations          `yaml:"cyclicRelations"`
+	Custom                   CustomRules              `yaml:"custom"`
 }
 
+// Target types of RuleWarn
+const (
+	TargetTypeSchema     = "schema"
+	TargetTypeTable      = "table"
+	TargetTypeColumn     = "column"
+	TargetTypeIndex      = "index"
+	TargetTypeConstraint = "constraint"
+	TargetTypeTrigger    = "trigger"
+	TargetTypeRelation   = "relation"
+)
+
+// Severities of RuleWarn
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Severities are the severities of RuleWarn in ascending order
+var Severities = []string{SeverityInfo, SeverityWarning, SeverityError}
+
+// DefaultSeverity is the severity of the rule without `severity:`
+const DefaultSeverity = SeverityError
+
 // RuleWarn is struct of Rule error
 type RuleWarn struct {
-	Target  string
-	Message string
+	RuleID     string `json:"rule_id"`
+	Severity   string `json:"severity"`
+	TargetType string `json:"target_type"`
+	Target     string `json:"target"`
+	Message    string `json:"message"`
+	// Discriminator distinguishes warns of the same rule and target ( e.g. the missing column of requireColumns ).
+	// It must not contain values that change over time, because it is a part of the fingerprint of lint baseline.
+	Discriminator string `json:"-"`
+}
+
+// IsAtLeast return whether the severity of RuleWarn is equal to or higher than the severity
+func (w RuleWarn) IsAtLeast(severity string) bool {
+	return slices.Index(Severities, w.Severity) >= slices.Index(Severities, severity)
 }
 
 // Rule is interfece of `tbls lint` cop
@@ -38,9 +83,48 @@ type Rule interface {
 	Check(schema *schema.Schema, exclude []string) []RuleWarn
 }
 
+// Check runs all rules and returns warns with the rule ID ( the key of the rule in the config ) and the severity of the rule
+func (l *Lint) Check(s *schema.Schema, exclude []string) ([]RuleWarn, error) {
+	warns := []RuleWarn{}
+	v := reflect.Indirect(reflect.ValueOf(l))
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		r, ok := v.Field(i).Interface().(Rule)
+		if !ok {
+			return nil, fmt.Errorf("invalid rule: %v", v.Field(i).Interface())
+		}
+		if vr, ok := r.(interface{ Validate() error }); ok {
+			if err := vr.Validate(); err != nil {
+				return nil, err
+			}
+		}
+		id := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		severity := DefaultSeverity
+		if f := v.Field(i); f.Kind() == reflect.Struct {
+			if sf := f.FieldByName("Severity"); sf.IsValid() && sf.String() != "" {
+				severity = sf.String()
+			}
+		}
+		if !slices.Contains(Severities, severity) {
+			return nil, fmt.Errorf("invalid severity of %s: %s", id, severity)
+		}
+		for _, w := range r.Check(s, exclude) {
+			if w.RuleID == "" {
+				w.RuleID = id
+			}
+			if w.Severity == "" {
+				w.Severity = severity
+			}
+			warns = append(warns, w)
+		}
+	}
+	return warns, nil
+}
+
 // RequireTableComment checks table comment
 type RequireTableComment struct {
 	Enabled      bool     `yaml:"enabled"`
+	Severity     string   `yaml:"severity"`
 	AllOrNothing bool     `yaml:"allOrNothing"`
 	Exclude      []string `yaml:"exclude"`
 }
@@ -70,8 +154,9 @@ func (r RequireTableComment) Check(s *schema.Schema, exclude []string) []RuleWar
 		}
 		if t.Comment == "" {
 			warns = append(warns, RuleWarn{
-				Target:  t.Name,
-				Message: msg,
+				TargetType: TargetTypeTable,
+				Target:     t.Name,
+				Message:    msg,
 			})
 			continue
 		}
@@ -86,6 +171,7 @@ func (r RequireTableComment) Check(s *schema.Schema, exclude []string) []RuleWar
 // RequireColumnComment checks column comment
 type RequireColumnComment struct {
 	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
 	AllOrNothing  bool     `yaml:"allOrNothing"`
 	Exclude       []string `yaml:"exclude"`
 	ExcludeTables []string `yaml:"excludeTables"`
@@ -121,8 +207,9 @@ func (r RequireColumnComment) Check(s *schema.Schema, exclude []string) []RuleWa
 			}
 			if c.Comment == "" {
 				warns = append(warns, RuleWarn{
-					Target:  target,
-					Message: msg,
+					TargetType: TargetTypeColumn,
+					Target:     target,
+					Message:    msg,
 				})
 				continue
 			}
@@ -138,6 +225,7 @@ func (r RequireColumnComment) Check(s *schema.Schema, exclude []string) []RuleWa
 // RequireIndexComment checks index comment
 type RequireIndexComment struct {
 	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
 	AllOrNothing  bool     `yaml:"allOrNothing"`
 	Exclude       []string `yaml:"exclude"`
 	ExcludeTables []string `yaml:"excludeTables"`
@@ -173,8 +261,9 @@ func (r RequireIndexComment) Check(s *schema.Schema, exclude []string) []RuleWar
 			}
 			if i.Comment == "" {
 				warns = append(warns, RuleWarn{
-					Target:  target,
-					Message: msg,
+					TargetType: TargetTypeIndex,
+					Target:     target,
+					Message:    msg,
 				})
 				continue
 			}
@@ -190,6 +279,7 @@ func (r RequireIndexComment) Check(s *schema.Schema, exclude []string) []RuleWar
 // RequireConstraintComment checks constraint comment
 type RequireConstraintComment struct {
 	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
 	AllOrNothing  bool     `yaml:"allOrNothing"`
 	Exclude       []string `yaml:"exclude"`
 	ExcludeTables []string `yaml:"excludeTables"`
@@ -225,8 +315,9 @@ func (r RequireConstraintComment) Check(s *schema.Schema, exclude []string) []Ru
 			}
 			if c.Comment == "" {
 				warns = append(warns, RuleWarn{
-					Target:  target,
-					Message: msg,
+					TargetType: TargetTypeConstraint,
+					Target:     target,
+					Message:    msg,
 				})
 				continue
 			}
@@ -242,6 +333,7 @@ func (r RequireConstraintComment) Check(s *schema.Schema, exclude []string) []Ru
 // RequireTriggerComment checks trigger comment
 type RequireTriggerComment struct {
 	Enabled       bool     `yaml:"enabled"`
+	Severity      string   `yaml:"severity"`
 	AllOrNothing  bool     `yaml:"allOrNothing"`
 	Exclude       []string `yaml:"exclude"`
 	ExcludeTables []string `yaml:"excludeTables"`
@@ -277,8 +369,9 @@ func (r RequireTriggerComment) Check(s *schema.Schema, exclude []string) []RuleW
 			}
 			if trig.Comment == "" {
 				warns = append(warns, RuleWarn{
-					Target:  target,
-					Message: msg,
+					TargetType: TargetTypeTrigger,
+					Target:     target,
+					Message:    msg,
 				})
 				continue
 			}
@@ -294,6 +387,7 @@ func (r RequireTriggerComment) Check(s *schema.Schema, exclude []string) []RuleW
 // RequireTableLabels checks table labels
 type RequireTableLabels struct {
 	Enabled      bool     `yaml:"enabled"`
+	Severity     string   `yaml:"severity"`
 	AllOrNothing bool     `yaml:"allOrNothing"`
 	Exclude      []string `yaml:"exclude"`
 }
@@ -324,8 +418,9 @@ func (r RequireTableLabels) Check(s *schema.Schema, exclude []string) []RuleWarn
 		if len(t.Labels) == 0 {
 			target := t.Name
 			warns = append(warns, RuleWarn{
-				Target:  target,
-				Message: msg,
+				TargetType: TargetTypeTable,
+				Target:     target,
+				Message:    msg,
 			})
 			continue
 		}
@@ -341,6 +436,7 @@ func (r RequireTableLabels) Check(s *schema.Schema, exclude []string) []RuleWarn
 // UnrelatedTable checks isolated table
 type UnrelatedTable struct {
 	Enabled      bool     `yaml:"enabled"`
+	Severity     string   `yaml:"severity"`
 	AllOrNothing bool     `yaml:"allOrNothing"`
 	Exclude      []string `yaml:"exclude"`
 }
@@ -384,9 +480,12 @@ func (r UnrelatedTable) Check(s *schema.Schema, exclude []string) []RuleWarn {
 		for _, t := range ut {
 			us = append(us, t.Name)
 		}
+		slices.Sort(us)
 		warns = append(warns, RuleWarn{
-			Target:  s.Name,
-			Message: fmt.Sprintf(msgFmt, us),
+			TargetType:    TargetTypeSchema,
+			Target:        s.Name,
+			Message:       fmt.Sprintf(msgFmt, us),
+			Discriminator: strings.Join(us, ","),
 		})
 	}
 	if r.AllOrNothing && !related {
@@ -397,9 +496,10 @@ func (r UnrelatedTable) Check(s *schema.Schema, exclude []string) []RuleWarn {
 
 // ColumnCount checks table column count
 type ColumnCount struct {
-	Enabled bool     `yaml:"enabled"`
-	Max     int      `yaml:"max"`
-	Exclude []string `yaml:"exclude"`
+	Enabled  bool     `yaml:"enabled"`
+	Severity string   `yaml:"severity"`
+	Max      int      `yaml:"max"`
+	Exclude  []string `yaml:"exclude"`
 }
 
 // IsEnabled return Rule is enabled or not
@@ -425,8 +525,9 @@ func (r ColumnCount) Check(s *schema.Schema, exclude []string) []RuleWarn {
 		}
 		if len(t.Columns) > r.Max {
 			warns = append(warns, RuleWarn{
-				Target:  t.Name,
-				Message: fmt.Sprintf(msgFmt, len(t.Columns), r.Max),
+				TargetType: TargetTypeTable,
+				Target:     t.Name,
+				Message:    fmt.Sprintf(msgFmt, len(t.Columns), r.Max),
 			})
 		}
 	}
@@ -435,8 +536,9 @@ func (r ColumnCount) Check(s *schema.Schema, exclude []string) []RuleWarn {
 
 // RequireColumns checks if the table has specified columns
 type RequireColumns struct {
-	Enabled bool                   `yaml:"enabled"`
-	Columns []RequireColumnsColumn `yaml:"columns"`
+	Enabled  bool                   `yaml:"enabled"`
+	Severity string                 `yaml:"severity"`
+	Columns  []RequireColumnsColumn `yaml:"columns"`
 }
 
 // RequireColumnsColumn is required column
@@ -477,8 +579,10 @@ func (r RequireColumns) Check(s *schema.Schema, exclude []string) []RuleWarn {
 			}
 			if !exists {
 				warns = append(warns, RuleWarn{
-					Target:  t.Name,
-					Message: fmt.Sprintf(msgFmt, cc.Name),
+					TargetType:    TargetTypeTable,
+					Target:        t.Name,
+					Message:       fmt.Sprintf(msgFmt, cc.Name),
+					Discriminator: cc.Name,
 				})
 			}
 		}
@@ -488,7 +592,8 @@ func (r RequireColumns) Check(s *schema.Schema, exclude []string) []RuleWarn {
 
 // DuplicateRelations checks duplicate table relations
 type DuplicateRelations struct {
-	Enabled bool `yaml:"enabled"`
+	Enabled  bool   `yaml:"enabled"`
+	Severity string `yaml:"severity"`
 }
 
 // IsEnabled return Rule is enabled or not
@@ -526,8 +631,10 @@ func (r DuplicateRelations) Check(s *schema.Schema, exclude []string) []RuleWarn
 		key := [4]string{r.Table.Name, r.ParentTable.Name, fmt.Sprintf("%v", columns), fmt.Sprintf("%v", parentColumns)}
 		if _, dup := relations[key]; dup {
 			warns = append(warns, RuleWarn{
-				Target:  r.Table.Name,
-				Message: fmt.Sprintf(msgFmt, r.Table.Name, r.ParentTable.Name),
+				TargetType:    TargetTypeRelation,
+				Target:        r.Table.Name,
+				Message:       fmt.Sprintf(msgFmt, r.Table.Name, r.ParentTable.Name),
+				Discriminator: relationTarget(r),
 			})
 		}
 		relations[key] = true
@@ -538,8 +645,9 @@ func (r DuplicateRelations) Check(s *schema.Schema, exclude []string) []RuleWarn
 
 // RequireForeignKeyIndex checks if the foreign key columns have an index
 type RequireForeignKeyIndex struct {
-	Enabled bool     `yaml:"enabled"`
-	Exclude []string `yaml:"exclude"`
+	Enabled  bool     `yaml:"enabled"`
+	Severity string   `yaml:"severity"`
+	Exclude  []string `yaml:"exclude"`
 }
 
 // IsEnabled return Rule is enabled or not
@@ -578,8 +686,9 @@ func (r RequireForeignKeyIndex) Check(s *schema.Schema, exclude []string) []Rule
 				}
 				if !exist {
 					warns = append(warns, RuleWarn{
-						Target:  target,
-						Message: fmt.Sprintf(msgFmt, t.Name),
+						TargetType: TargetTypeColumn,
+						Target:     target,
+						Message:    fmt.Sprintf(msgFmt, t.Name),
 					})
 				}
 			}
@@ -591,8 +700,9 @@ func (r RequireForeignKeyIndex) Check(s *schema.Schema, exclude []string) []Rule
 
 // LabelStyleBigQuery checks if labels are in BigQuery style ( https://cloud.google.com/resource-manager/docs/creating-managing-labels#requirements )
 type LabelStyleBigQuery struct {
-	Enabled bool     `yaml:"enabled"`
-	Exclude []string `yaml:"exclude"`
+	Enabled  bool     `yaml:"enabled"`
+	Severity string   `yaml:"severity"`
+	Exclude  []string `yaml:"exclude"`
 }
 
 // IsEnabled return Rule is enabled or not
@@ -613,8 +723,9 @@ func (r LabelStyleBigQuery) Check(s *schema.Schema, exclude []string) []RuleWarn
 		if !checkLabelStyleBigQuery(l.Name) {
 			target := fmt.Sprintf("%s.Labels.%s", s.Name, l.Name)
 			warns = append(warns, RuleWarn{
-				Target:  target,
-				Message: fmt.Sprintf(msgFmtSchema, l.Name, s.Name),
+				TargetType: TargetTypeSchema,
+				Target:     target,
+				Message:    fmt.Sprintf(msgFmtSchema, l.Name, s.Name),
 			})
 		}
 	}
@@ -631,8 +742,9 @@ func (r LabelStyleBigQuery) Check(s *schema.Schema, exclude []string) []RuleWarn
 			if !checkLabelStyleBigQuery(l.Name) {
 				target := fmt.Sprintf("%s.Labels.%s", t.Name, l.Name)
 				warns = append(warns, RuleWarn{
-					Target:  target,
-					Message: fmt.Sprintf(msgFmt, l.Name, t.Name),
+					TargetType: TargetTypeTable,
+					Target:     target,
+					Message:    fmt.Sprintf(msgFmt, l.Name, t.Name),
 				})
 			}
 		}
@@ -668,8 +780,9 @@ func checkLabelStyleBigQuery(label string) bool {
 
 // RequireViewpoints checks if the table is included in any viewpoints.
 type RequireViewpoints struct {
-	Enabled bool     `yaml:"enabled"`
-	Exclude []string `yaml:"exclude"`
+	Enabled  bool     `yaml:"enabled"`
+	Severity string   `yaml:"severity"`
+	Exclude  []string `yaml:"exclude"`
 }
 
 // IsEnabled return Rule is enabled or not
@@ -703,10 +816,647 @@ T:
 			}
 		}
 		warns = append(warns, RuleWarn{
-			Target:  t.Name,
-			Message: fmt.Sprintf(msgFmt, t.Name),
+			TargetType: TargetTypeTable,
+			Target:     t.Name,
+			Message:    fmt.Sprintf(msgFmt, t.Name),
+		})
+	}
+
+	return warns
+}
+
+// NamingConvention checks if the names of tables, columns, indexes and constraints match the patterns
+type NamingConvention struct {
+	Enabled    bool     `yaml:"enabled"`
+	Severity   string   `yaml:"severity"`
+	Table      string   `yaml:"table"`
+	Column     string   `yaml:"column"`
+	Index      string   `yaml:"index"`
+	PrimaryKey string   `yaml:"primaryKey"`
+	ForeignKey string   `yaml:"foreignKey"`
+	Unique     string   `yaml:"unique"`
+	TableForm  string   `yaml:"tableForm"`
+	Exclude    []string `yaml:"exclude"`
+}
+
+// Table forms of NamingConvention
+const (
+	TableFormPlural   = "plural"
+	TableFormSingular = "singular"
+)
+
+var namingPlaceholderRe = regexp.MustCompile(`\{(table|columns|parent_table|parent_columns)\}`)
+
+// IsEnabled return Rule is enabled or not
+func (r NamingConvention) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Validate the patterns
+func (r NamingConvention) Validate() error {
+	for _, p := range []string{r.Table, r.Column, r.Index, r.PrimaryKey, r.ForeignKey, r.Unique} {
+		if _, _, err := namingPattern(p, map[string]string{}); err != nil {
+			return fmt.Errorf("invalid namingConvention pattern: %w", err)
+		}
+	}
+	switch r.TableForm {
+	case "", TableFormPlural, TableFormSingular:
+	default:
+		return fmt.Errorf("invalid namingConvention tableForm: %s", r.TableForm)
+	}
+	return nil
+}
+
+// Check if the names match the patterns
+func (r NamingConvention) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns
+	}
+	msgFmt := "%s name `%s` does not match the naming convention. [%s]"
+	msgFmtForm := "table name `%s` is not %s."
+
+	check := func(pattern, kind, name, targetType, target string, values map[string]string) {
+		if pattern == "" || match(r.Exclude, name) || match(r.Exclude, target) {
+			return
+		}
+		p, re, err := namingPattern(pattern, values)
+		if err != nil {
+			return
+		}
+		if !re.MatchString(name) {
+			warns = append(warns, RuleWarn{
+				TargetType: targetType,
+				Target:     target,
+				Message:    fmt.Sprintf(msgFmt, kind, name, p),
+			})
+		}
+	}
+
+	nt := s.NormalizeTableNames(r.Exclude)
+	for _, t := range s.Tables {
+		if match(exclude, t.Name) {
+			continue
+		}
+		if match(nt, t.Name) {
+			continue
+		}
+		tn := unqualifiedName(t.Name)
+		check(r.Table, "table", tn, TargetTypeTable, t.Name, nil)
+		if r.TableForm != "" && !match(r.Exclude, tn) {
+			words := strings.Split(tn, "_")
+			last := words[len(words)-1]
+			if (r.TableForm == TableFormPlural && !pluralizeClient.IsPlural(last)) || (r.TableForm == TableFormSingular && !pluralizeClient.IsSingular(last)) {
+				warns = append(warns, RuleWarn{
+					TargetType:    TargetTypeTable,
+					Target:        t.Name,
+					Message:       fmt.Sprintf(msgFmtForm, tn, r.TableForm),
+					Discriminator: "tableForm",
+				})
+			}
+		}
+		for _, c := range t.Columns {
+			check(r.Column, "column", c.Name, TargetTypeColumn, fmt.Sprintf("%s.%s", t.Name, c.Name), map[string]string{"table": tn})
+		}
+		constraints := map[string]struct{}{}
+		for _, c := range t.Constraints {
+			constraints[c.Name] = struct{}{}
+			values := map[string]string{
+				"table":   tn,
+				"columns": strings.Join(c.Columns, "_"),
+			}
+			target := fmt.Sprintf("%s.%s", t.Name, c.Name)
+			switch c.Type {
+			case "PRIMARY KEY":
+				check(r.PrimaryKey, "primary key", c.Name, TargetTypeConstraint, target, values)
+			case schema.TypeFK:
+				if c.ReferencedTable != nil {
+					values["parent_table"] = unqualifiedName(*c.ReferencedTable)
+				}
+				values["parent_columns"] = strings.Join(c.ReferencedColumns, "_")
+				check(r.ForeignKey, "foreign key", c.Name, TargetTypeConstraint, target, values)
+			case "UNIQUE":
+				check(r.Unique, "unique", c.Name, TargetTypeConstraint, target, values)
+			}
+		}
+		for _, i := range t.Indexes {
+			// indexes for constraints are checked as constraints
+			if _, ok := constraints[i.Name]; ok {
+				continue
+			}
+			values := map[string]string{
+				"table":   tn,
+				"columns": strings.Join(i.Columns, "_"),
+			}
+			check(r.Index, "index", i.Name, TargetTypeIndex, fmt.Sprintf("%s.%s", t.Name, i.Name), values)
+		}
+	}
+	return warns
+}
+
+// namingPattern return the pattern replacing placeholders ( `{table}` , `{columns}` ... ) with the quoted values,
+// and the regexp of it matching the whole name ( an unanchored `[a-z_]+` must not accept `UserAccounts` )
+func namingPattern(pattern string, values map[string]string) (string, *regexp.Regexp, error) {
+	p := namingPlaceholderRe.ReplaceAllStringFunc(pattern, func(m string) string {
+		v, ok := values[m[1:len(m)-1]]
+		if !ok {
+			return ".*"
+		}
+		return regexp.QuoteMeta(v)
+	})
+	re, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", p))
+	if err != nil {
+		return "", nil, err
+	}
+	return p, re, nil
+}
+
+func unqualifiedName(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// ForeignKeyTypeMismatch checks if the types of the foreign key columns are the same as the types of the parent columns
+type ForeignKeyTypeMismatch struct {
+	Enabled  bool     `yaml:"enabled"`
+	Severity string   `yaml:"severity"`
+	Exclude  []string `yaml:"exclude"`
+	// EquivalentTypes is the groups of equivalent types per driver. e.g. `postgres: [[uuid, varchar(36)]]`
+	EquivalentTypes map[string][][]string `yaml:"equivalentTypes"`
+}
+
+// defaultEquivalentTypes are the aliases of types per driver
+var defaultEquivalentTypes = map[string][][]string{
+	"postgres": {
+		{"integer", "int", "int4"},
+		{"bigint", "int8"},
+		{"smallint", "int2"},
+		{"character varying", "varchar"},
+		{"character", "char"},
+		{"boolean", "bool"},
+		{"timestamp without time zone", "timestamp"},
+		{"timestamp with time zone", "timestamptz"},
+		{"double precision", "float8"},
+		{"real", "float4"},
+	},
+	"mysql": {
+		{"int", "integer"},
+		{"boolean", "bool", "tinyint(1)"},
+		{"decimal", "dec", "numeric"},
+	},
+	"sqlite": {
+		{"integer", "int"},
+	},
+}
+
+var (
+	typeParamsRe          = regexp.MustCompile(`^([^(]+?)\s*(\([^)]*\))$`)
+	integerDisplayWidthRe = regexp.MustCompile(`^(tinyint|smallint|mediumint|int|integer|bigint)\(\d+\)`)
+)
+
+// IsEnabled return Rule is enabled or not
+func (r ForeignKeyTypeMismatch) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Check if the types of the foreign key columns are the same as the types of the parent columns
+func (r ForeignKeyTypeMismatch) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns
+	}
+	msgFmt := "foreign key column type mismatch. [%s (%s) -> %s (%s)]"
+
+	driver := ""
+	if s.Driver != nil {
+		driver = s.Driver.Name
+	}
+	groups := append(append([][]string{}, defaultEquivalentTypes[driver]...), r.EquivalentTypes[driver]...)
+	canonical := typeCanonicalizer(groups)
+
+	nt := s.NormalizeTableNames(r.Exclude)
+	for _, rel := range s.Relations {
+		if match(exclude, rel.Table.Name) || match(nt, rel.Table.Name) {
+			continue
+		}
+		if len(rel.Columns) != len(rel.ParentColumns) {
+			continue
+		}
+		for i, c := range rel.Columns {
+			pc := rel.ParentColumns[i]
+			target := fmt.Sprintf("%s.%s", rel.Table.Name, c.Name)
+			if match(r.Exclude, c.Name) || match(r.Exclude, target) {
+				continue
+			}
+			if canonical(c.Type) == canonical(pc.Type) {
+				continue
+			}
+			warns = append(warns, RuleWarn{
+				TargetType:    TargetTypeColumn,
+				Target:        target,
+				Message:       fmt.Sprintf(msgFmt, target, c.Type, fmt.Sprintf("%s.%s", rel.ParentTable.Name, pc.Name), pc.Type),
+				Discriminator: fmt.Sprintf("%s.%s", rel.ParentTable.Name, pc.Name),
+			})
+		}
+	}
+	return warns
+}
+
+// typeCanonicalizer return the function which return the canonical type. The types in the same group are canonicalized to the first type of the group.
+func typeCanonicalizer(groups [][]string) func(string) string {
+	normalize := func(t string) string {
+		t = strings.Join(strings.Fields(strings.ToLower(t)), " ")
+		// `int(11)` is the same as `int`
+		return integerDisplayWidthRe.ReplaceAllString(t, "$1")
+	}
+	aliases := map[string]string{}
+	for _, g := range groups {
+		if len(g) == 0 {
+			continue
+		}
+		c := normalize(g[0])
+		if a, ok := aliases[c]; ok {
+			c = a
+		}
+		for _, t := range g {
+			aliases[normalize(t)] = c
+		}
+	}
+	return func(t string) string {
+		t = normalize(t)
+		if a, ok := aliases[t]; ok {
+			return a
+		}
+		// `varchar(36)` is the same as `character varying(36)`
+		m := typeParamsRe.FindStringSubmatch(t)
+		if m == nil {
+			return t
+		}
+		if a, ok := aliases[m[1]]; ok {
+			return a + m[2]
+		}
+		return t
+	}
+}
+
+// RedundantIndex checks duplicate indexes and indexes which are left-prefixes of another index
+type RedundantIndex struct {
+	Enabled  bool     `yaml:"enabled"`
+	Severity string   `yaml:"severity"`
+	Exclude  []string `yaml:"exclude"`
+}
+
+var indexMethodRe = regexp.MustCompile(`(?i)\sUSING\s+(\w+)`)
+
+// IsEnabled return Rule is enabled or not
+func (r RedundantIndex) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Check duplicate indexes and indexes which are left-prefixes of another index
+func (r RedundantIndex) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns
+	}
+	msgFmtDuplicate := "duplicate index. [%s is the same as %s]"
+	msgFmtPrefix := "redundant index. [%s is a left-prefix of %s]"
+
+	nt := s.NormalizeTableNames(r.Exclude)
+	for _, t := range s.Tables {
+		if match(exclude, t.Name) {
+			continue
+		}
+		if match(nt, t.Name) {
+			continue
+		}
+		for i, a := range t.Indexes {
+			target := fmt.Sprintf("%s.%s", t.Name, a.Name)
+			if match(r.Exclude, a.Name) || match(r.Exclude, target) {
+				continue
+			}
+			if len(a.Columns) == 0 || isPartialIndex(a) {
+				continue
+			}
+			aUnique := isUniqueIndex(t, a)
+			for j, b := range t.Indexes {
+				if i == j || len(a.Columns) > len(b.Columns) || isPartialIndex(b) || indexMethod(a) != indexMethod(b) {
+					continue
+				}
+				if !slices.Equal(a.Columns, b.Columns[:len(a.Columns)]) {
+					continue
+				}
+				bUnique := isUniqueIndex(t, b)
+				if len(a.Columns) == len(b.Columns) {
+					// report only one of the duplicate indexes. unique index takes precedence
+					if aUnique && !bUnique || aUnique == bUnique && i < j {
+						continue
+					}
+					warns = append(warns, RuleWarn{
+						TargetType: TargetTypeIndex,
+						Target:     target,
+						Message:    fmt.Sprintf(msgFmtDuplicate, a.Name, b.Name),
+					})
+					break
+				}
+				// unique index is not redundant because the semantics differ
+				if aUnique {
+					continue
+				}
+				warns = append(warns, RuleWarn{
+					TargetType: TargetTypeIndex,
+					Target:     target,
+					Message:    fmt.Sprintf(msgFmtPrefix, a.Name, b.Name),
+				})
+				break
+			}
+		}
+	}
+	return warns
+}
+
+func isUniqueIndex(t *schema.Table, i *schema.Index) bool {
+	def := strings.ToUpper(i.Def)
+	if strings.Contains(def, "UNIQUE") || strings.Contains(def, "PRIMARY KEY") {
+		return true
+	}
+	for _, c := range t.Constraints {
+		if c.Name == i.Name && (c.Type == "PRIMARY KEY" || c.Type == "UNIQUE") {
+			return true
+		}
+	}
+	return false
+}
+
+func isPartialIndex(i *schema.Index) bool {
+	return strings.Contains(strings.ToUpper(i.Def), " WHERE ")
+}
+
+func indexMethod(i *schema.Index) string {
+	m := indexMethodRe.FindStringSubmatch(i.Def)
+	if m == nil {
+		return "btree"
+	}
+	return strings.ToLower(m[1])
+}
+
+// RequirePrimaryKey checks if the base table has a primary key
+type RequirePrimaryKey struct {
+	Enabled  bool     `yaml:"enabled"`
+	Severity string   `yaml:"severity"`
+	Exclude  []string `yaml:"exclude"`
+}
+
+// IsEnabled return Rule is enabled or not
+func (r RequirePrimaryKey) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Check if the base table has a primary key
+func (r RequirePrimaryKey) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns
+	}
+	msg := "primary key required."
+
+	nt := s.NormalizeTableNames(r.Exclude)
+T:
+	for _, t := range s.Tables {
+		if match(exclude, t.Name) {
+			continue
+		}
+		if match(nt, t.Name) {
+			continue
+		}
+		if !isBaseTable(t) {
+			continue
+		}
+		for _, c := range t.Columns {
+			if c.PK {
+				continue T
+			}
+		}
+		warns = append(warns, RuleWarn{
+			TargetType: TargetTypeTable,
+			Target:     t.Name,
+			Message:    msg,
 		})
 	}
+	return warns
+}
+
+// NullableIdentifierColumn checks nullable identifier-like columns ( `id` , `*_id` ) which are not part of any relation
+type NullableIdentifierColumn struct {
+	Enabled  bool     `yaml:"enabled"`
+	Severity string   `yaml:"severity"`
+	Patterns []string `yaml:"patterns"`
+	Exclude  []string `yaml:"exclude"`
+}
+
+var defaultIdentifierColumnPatterns = []string{"id", "*_id"}
+
+// IsEnabled return Rule is enabled or not
+func (r NullableIdentifierColumn) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Check nullable identifier-like columns which are not part of any relation
+func (r NullableIdentifierColumn) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns
+	}
+	msg := "identifier column is nullable and not part of any relation."
+	patterns := r.Patterns
+	if len(patterns) == 0 {
+		patterns = defaultIdentifierColumnPatterns
+	}
+
+	nt := s.NormalizeTableNames(r.Exclude)
+	for _, t := range s.Tables {
+		if match(exclude, t.Name) {
+			continue
+		}
+		if match(nt, t.Name) {
+			continue
+		}
+		if !isBaseTable(t) {
+			continue
+		}
+		for _, c := range t.Columns {
+			target := fmt.Sprintf("%s.%s", t.Name, c.Name)
+			if match(r.Exclude, c.Name) || match(r.Exclude, target) {
+				continue
+			}
+			if !c.Nullable || !match(patterns, strings.ToLower(c.Name)) {
+				continue
+			}
+			if len(c.ParentRelations) > 0 || len(c.ChildRelations) > 0 {
+				continue
+			}
+			warns = append(warns, RuleWarn{
+				TargetType: TargetTypeColumn,
+				Target:     target,
+				Message:    msg,
+			})
+		}
+	}
+	return warns
+}
+
+// relationTarget return the target name of the relation like `posts(user_id) -> users(id)`
+func relationTarget(r *schema.Relation) string {
+	names := func(cs []*schema.Column) string {
+		n := []string{}
+		for _, c := range cs {
+			n = append(n, c.Name)
+		}
+		return strings.Join(n, ", ")
+	}
+	return fmt.Sprintf("%s(%s) -> %s(%s)", r.Table.Name, names(r.Columns), r.ParentTable.Name, names(r.ParentColumns))
+}
+
+// isBaseTable return whether the table is a base table ( not a view )
+func isBaseTable(t *schema.Table) bool {
+	switch strings.ToUpper(t.Type) {
+	case "BASE TABLE", "BASIC TABLE", "TABLE":
+		return true
+	}
+	return false
+}
+
+// ReservedKeyword checks if table and column names are reserved keywords
+type ReservedKeyword struct {
+	Enabled  bool   `yaml:"enabled"`
+	Severity string `yaml:"severity"`
+	// Dialects to check. default: the dialect of the driver
+	Dialects []string `yaml:"dialects"`
+	// AllDialects checks against all dialects for portability
+	AllDialects bool     `yaml:"allDialects"`
+	Exclude     []string `yaml:"exclude"`
+}
+
+// IsEnabled return Rule is enabled or not
+func (r ReservedKeyword) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Validate dialects
+func (r ReservedKeyword) Validate() error {
+	for _, d := range r.Dialects {
+		if _, ok := reservedKeywords[d]; !ok {
+			return fmt.Errorf("unsupported reservedKeyword dialect: %s (%s)", d, strings.Join(ReservedKeywordDialects(), ", "))
+		}
+	}
+	return nil
+}
+
+// Check if table and column names are reserved keywords
+func (r ReservedKeyword) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns
+	}
+	msgFmt := "%s name `%s` is a reserved keyword. [%s]"
+
+	dialects := r.Dialects
+	switch {
+	case r.AllDialects:
+		dialects = ReservedKeywordDialects()
+	case len(dialects) == 0 && s.Driver != nil:
+		if d, ok := reservedKeywordDialects[s.Driver.Name]; ok {
+			dialects = []string{d}
+		}
+	}
+	if len(dialects) == 0 {
+		return warns
+	}
+
+	nt := s.NormalizeTableNames(r.Exclude)
+	for _, t := range s.Tables {
+		if match(exclude, t.Name) {
+			continue
+		}
+		if match(nt, t.Name) {
+			continue
+		}
+		tn := unqualifiedName(t.Name)
+		if in := reservedIn(tn, dialects); len(in) > 0 {
+			warns = append(warns, RuleWarn{
+				TargetType: TargetTypeTable,
+				Target:     t.Name,
+				Message:    fmt.Sprintf(msgFmt, "table", tn, strings.Join(in, ", ")),
+			})
+		}
+		for _, c := range t.Columns {
+			target := fmt.Sprintf("%s.%s", t.Name, c.Name)
+			if match(r.Exclude, c.Name) || match(r.Exclude, target) {
+				continue
+			}
+			if in := reservedIn(c.Name, dialects); len(in) > 0 {
+				warns = append(warns, RuleWarn{
+					TargetType: TargetTypeColumn,
+					Target:     target,
+					Message:    fmt.Sprintf(msgFmt, "column", c.Name, strings.Join(in, ", ")),
+				})
+			}
+		}
+	}
+	return warns
+}
 
+// CyclicRelations checks if tables reference each other cyclically
+type CyclicRelations struct {
+	Enabled            bool   `yaml:"enabled"`
+	Severity           string `yaml:"severity"`
+	AllowSelfReference bool   `yaml:"allowSelfReference"`
+	// Allow is the list of tables allowed to be in a cycle. A cycle is allowed when all tables in it match.
+	Allow []string `yaml:"allow"`
+}
+
+// IsEnabled return Rule is enabled or not
+func (r CyclicRelations) IsEnabled() bool {
+	return r.Enabled
+}
+
+// Check if tables reference each other cyclically
+func (r CyclicRelations) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns
+	}
+	msgFmt := "tables reference each other cyclically. [%s]"
+	allow := s.NormalizeTableNames(r.Allow)
+L:
+	for _, cycle := range s.Cycles() {
+		if r.AllowSelfReference && len(cycle) == 1 {
+			continue
+		}
+		allowed := true
+		names := []string{}
+		for _, t := range cycle {
+			if match(exclude, t.Name) {
+				continue L
+			}
+			if !match(allow, t.Name) {
+				allowed = false
+			}
+			names = append(names, t.Name)
+		}
+		if allowed {
+			continue
+		}
+		members := slices.Clone(names)
+		slices.Sort(members)
+		warns = append(warns, RuleWarn{
+			TargetType:    TargetTypeTable,
+			Target:        cycle[0].Name,
+			Message:       fmt.Sprintf(msgFmt, strings.Join(names, ", ")),
+			Discriminator: strings.Join(members, ","),
+		})
+	}
 	return warns
 }
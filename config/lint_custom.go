@@ -0,0 +1,212 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/k1LoW/tbls/schema"
+)
+
+// Target kinds of CustomRule
+const (
+	CustomRuleTargetTable      = "table"
+	CustomRuleTargetColumn     = "column"
+	CustomRuleTargetIndex      = "index"
+	CustomRuleTargetConstraint = "constraint"
+	CustomRuleTargetRelation   = "relation"
+)
+
+// CustomRuleTargets are the supported target kinds of CustomRule
+var CustomRuleTargets = []string{CustomRuleTargetTable, CustomRuleTargetColumn, CustomRuleTargetIndex, CustomRuleTargetConstraint, CustomRuleTargetRelation}
+
+// CustomRules is the user-defined rules written as expressions
+type CustomRules []*CustomRule
+
+// CustomRule is the user-defined rule. It warns the target when the condition is true.
+type CustomRule struct {
+	Name     string   `yaml:"name"`
+	Enabled  *bool    `yaml:"enabled"`
+	Severity string   `yaml:"severity"`
+	Target   string   `yaml:"target"`
+	Cond     string   `yaml:"cond"`
+	Message  string   `yaml:"message"`
+	Exclude  []string `yaml:"exclude"`
+
+	program *vm.Program
+	message *template.Template
+}
+
+// IsEnabled return Rule is enabled or not
+func (r CustomRules) IsEnabled() bool {
+	for _, cr := range r {
+		if cr.IsEnabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate compiles conditions and messages of the rules
+func (r CustomRules) Validate() error {
+	names := map[string]struct{}{}
+	for i, cr := range r {
+		if cr.Name == "" {
+			return fmt.Errorf("lint.custom[%d] name is required", i)
+		}
+		if _, ok := names[cr.Name]; ok {
+			return fmt.Errorf("duplicate lint.custom name: %s", cr.Name)
+		}
+		names[cr.Name] = struct{}{}
+		if cr.Severity != "" && !slices.Contains(Severities, cr.Severity) {
+			return fmt.Errorf("invalid severity of %s: %s", cr.Name, cr.Severity)
+		}
+		if err := cr.compile(); err != nil {
+			return fmt.Errorf("lint.custom %s: %w", cr.Name, err)
+		}
+	}
+	return nil
+}
+
+// Check all rules
+func (r CustomRules) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	for _, cr := range r {
+		warns = append(warns, cr.Check(s, exclude)...)
+	}
+	return warns
+}
+
+// IsEnabled return Rule is enabled or not. The rule is enabled unless `enabled: false`
+func (r *CustomRule) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// Check the targets by the condition
+func (r *CustomRule) Check(s *schema.Schema, exclude []string) []RuleWarn {
+	warns := []RuleWarn{}
+	if !r.IsEnabled() {
+		return warns
+	}
+	if err := r.compile(); err != nil {
+		return append(warns, r.warn(TargetTypeSchema, s.Name, err.Error()))
+	}
+
+	nt := s.NormalizeTableNames(r.Exclude)
+	for _, t := range s.Tables {
+		if match(exclude, t.Name) || match(nt, t.Name) {
+			continue
+		}
+		env := map[string]any{"schema": s, "table": t}
+		switch r.Target {
+		case CustomRuleTargetTable:
+			warns = append(warns, r.eval(env, TargetTypeTable, t.Name)...)
+		case CustomRuleTargetColumn:
+			for _, c := range t.Columns {
+				target := fmt.Sprintf("%s.%s", t.Name, c.Name)
+				if match(r.Exclude, c.Name) || match(r.Exclude, target) {
+					continue
+				}
+				env["column"] = c
+				warns = append(warns, r.eval(env, TargetTypeColumn, target)...)
+			}
+		case CustomRuleTargetIndex:
+			for _, i := range t.Indexes {
+				target := fmt.Sprintf("%s.%s", t.Name, i.Name)
+				if match(r.Exclude, i.Name) || match(r.Exclude, target) {
+					continue
+				}
+				env["index"] = i
+				warns = append(warns, r.eval(env, TargetTypeIndex, target)...)
+			}
+		case CustomRuleTargetConstraint:
+			for _, c := range t.Constraints {
+				target := fmt.Sprintf("%s.%s", t.Name, c.Name)
+				if match(r.Exclude, c.Name) || match(r.Exclude, target) {
+					continue
+				}
+				env["constraint"] = c
+				warns = append(warns, r.eval(env, TargetTypeConstraint, target)...)
+			}
+		case CustomRuleTargetRelation:
+			// relations are checked on the child table
+			for _, rel := range s.Relations {
+				if rel.Table != t || match(exclude, rel.ParentTable.Name) || match(nt, rel.ParentTable.Name) {
+					continue
+				}
+				env["relation"] = rel
+				warns = append(warns, r.eval(env, TargetTypeRelation, relationTarget(rel))...)
+			}
+		}
+	}
+	return warns
+}
+
+func (r *CustomRule) eval(env map[string]any, targetType, target string) []RuleWarn {
+	got, err := expr.Run(r.program, env)
+	if err != nil {
+		return []RuleWarn{r.warn(targetType, target, fmt.Sprintf("failed to evaluate cond: %s", err))}
+	}
+	if v, ok := got.(bool); !ok || !v {
+		return nil
+	}
+	b := new(strings.Builder)
+	if err := r.message.Execute(b, env); err != nil {
+		return []RuleWarn{r.warn(targetType, target, fmt.Sprintf("failed to render message: %s", err))}
+	}
+	return []RuleWarn{r.warn(targetType, target, b.String())}
+}
+
+func (r *CustomRule) warn(targetType, target, msg string) RuleWarn {
+	return RuleWarn{
+		RuleID:     r.Name,
+		Severity:   r.Severity,
+		TargetType: targetType,
+		Target:     target,
+		Message:    msg,
+	}
+}
+
+func (r *CustomRule) compile() error {
+	if r.program != nil {
+		return nil
+	}
+	env := map[string]any{
+		"schema": (*schema.Schema)(nil),
+		"table":  (*schema.Table)(nil),
+	}
+	switch r.Target {
+	case CustomRuleTargetTable:
+	case CustomRuleTargetColumn:
+		env["column"] = (*schema.Column)(nil)
+	case CustomRuleTargetIndex:
+		env["index"] = (*schema.Index)(nil)
+	case CustomRuleTargetConstraint:
+		env["constraint"] = (*schema.Constraint)(nil)
+	case CustomRuleTargetRelation:
+		env["relation"] = (*schema.Relation)(nil)
+	default:
+		return fmt.Errorf("unsupported target: %q (%s)", r.Target, strings.Join(CustomRuleTargets, ", "))
+	}
+	if r.Cond == "" {
+		return fmt.Errorf("cond is required")
+	}
+	program, err := expr.Compile(r.Cond, expr.Env(env), expr.AsBool())
+	if err != nil {
+		return fmt.Errorf("invalid cond: %w", err)
+	}
+	msg := r.Message
+	if msg == "" {
+		msg = fmt.Sprintf("violates custom rule `%s`.", r.Name)
+	}
+	tmpl, err := template.New(r.Name).Parse(msg)
+	if err != nil {
+		return fmt.Errorf("invalid message: %w", err)
+	}
+	r.program = program
+	r.message = tmpl
+	return nil
+}
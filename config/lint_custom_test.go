@@ -0,0 +1,97 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCustomRule(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		rule        *CustomRule
+		lintExclude []string
+		want        []RuleWarn
+	}{
+		{
+			&CustomRule{Name: "tableComment", Target: "table", Cond: `table.Comment == ""`, Message: "{{ .table.Name }} has no comment."},
+			[]string{},
+			[]RuleWarn{
+				{RuleID: "tableComment", TargetType: TargetTypeTable, Target: "table_a", Message: "table_a has no comment."},
+			},
+		},
+		{
+			&CustomRule{Name: "tableComment", Target: "table", Cond: `table.Comment == ""`, Enabled: &disabled},
+			[]string{},
+			[]RuleWarn{},
+		},
+		{
+			&CustomRule{Name: "tableComment", Target: "table", Cond: `table.Comment == ""`},
+			[]string{"table_a"},
+			[]RuleWarn{},
+		},
+		{
+			&CustomRule{Name: "textColumn", Severity: SeverityWarning, Target: "column", Cond: `column.Type == "text" && column.Nullable`, Exclude: []string{"column_b2"}},
+			[]string{},
+			[]RuleWarn{
+				{RuleID: "textColumn", Severity: SeverityWarning, TargetType: TargetTypeColumn, Target: "table_b.column_b1", Message: "violates custom rule `textColumn`."},
+			},
+		},
+		{
+			&CustomRule{Name: "indexSuffix", Target: "index", Cond: `!(index.Name endsWith "_key")`, Message: "{{ .index.Name }}"},
+			[]string{},
+			[]RuleWarn{
+				{RuleID: "indexSuffix", TargetType: TargetTypeIndex, Target: "table_a.a2_idx", Message: "a2_idx"},
+			},
+		},
+		{
+			&CustomRule{Name: "fkName", Target: "constraint", Cond: `constraint.Type == "FOREIGN KEY" && !(constraint.Name startsWith "fk_")`},
+			[]string{},
+			[]RuleWarn{
+				{RuleID: "fkName", TargetType: TargetTypeConstraint, Target: "table_a.a1_b1_fk", Message: "violates custom rule `fkName`."},
+			},
+		},
+		{
+			&CustomRule{Name: "relation", Target: "relation", Cond: `relation.ParentTable.Comment != ""`, Message: "{{ .relation.Table.Name }} -> {{ .relation.ParentTable.Name }}"},
+			[]string{},
+			[]RuleWarn{
+				{RuleID: "relation", TargetType: TargetTypeRelation, Target: "table_a(column_a1) -> table_b(column_b1)", Message: "table_a -> table_b"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule.Name, func(t *testing.T) {
+			if err := (CustomRules{tt.rule}).Validate(); err != nil {
+				t.Fatal(err)
+			}
+			s := newTestSchema(t)
+			got := tt.rule.Check(s, tt.lintExclude)
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCustomRulesValidate(t *testing.T) {
+	tests := []struct {
+		rules   CustomRules
+		wantErr bool
+	}{
+		{CustomRules{{Name: "a", Target: "table", Cond: `len(table.Columns) > 10`}}, false},
+		{CustomRules{{Target: "table", Cond: `true`}}, true},
+		{CustomRules{{Name: "a", Target: "table", Cond: `true`}, {Name: "a", Target: "column", Cond: `true`}}, true},
+		{CustomRules{{Name: "a", Target: "view", Cond: `true`}}, true},
+		{CustomRules{{Name: "a", Target: "table"}}, true},
+		{CustomRules{{Name: "a", Target: "table", Cond: `table.Name`}}, true},
+		{CustomRules{{Name: "a", Target: "table", Cond: `column.Name == ""`}}, true},
+		{CustomRules{{Name: "a", Target: "table", Cond: `true`, Message: "{{ .table.Name "}}, true},
+		{CustomRules{{Name: "a", Target: "table", Cond: `true`, Severity: "critical"}}, true},
+	}
+	for i, tt := range tests {
+		err := tt.rules.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("(%d) got %v\nwant error %v", i, err, tt.wantErr)
+		}
+	}
+}
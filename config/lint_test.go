@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/k1LoW/tbls/schema"
 )
 
@@ -271,6 +272,10 @@ func TestUnrelatedTable(t *testing.T) {
 			if warns[0].Message != tt.wantMsg {
 				t.Errorf("TestUnrelatedTable(%d): got %v\nwant %v", i, warns[0].Message, tt.wantMsg)
 			}
+			// the isolated tables distinguish the warn in lint baseline
+			if warns[0].Discriminator != "table_c" {
+				t.Errorf("TestUnrelatedTable(%d): got %v\nwant %v", i, warns[0].Discriminator, "table_c")
+			}
 		}
 		ns := newTestNoRelationSchema(t)
 		if warns := r.Check(ns, tt.lintExclude); len(warns) != tt.wantNoRelation {
@@ -717,3 +722,422 @@ func TestRequireViewpoints(t *testing.T) {
 		}
 	}
 }
+
+func TestLintCheck(t *testing.T) {
+	l := Lint{
+		RequireTableComment: RequireTableComment{
+			Enabled: true,
+		},
+		RequireColumnComment: RequireColumnComment{
+			Enabled:  true,
+			Severity: SeverityWarning,
+		},
+	}
+	s := newTestSchema(t)
+	warns, err := l.Check(s, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []RuleWarn{
+		{RuleID: "requireTableComment", Severity: SeverityError, TargetType: TargetTypeTable, Target: "table_a", Message: "table comment required."},
+		{RuleID: "requireColumnComment", Severity: SeverityWarning, TargetType: TargetTypeColumn, Target: "table_b.column_b1", Message: "column comment required."},
+	}
+	if len(warns) != len(want) {
+		t.Fatalf("got %v\nwant %v", warns, want)
+	}
+	for i := range want {
+		if warns[i] != want[i] {
+			t.Errorf("got %v\nwant %v", warns[i], want[i])
+		}
+	}
+
+	l.RequireTableComment.Severity = "critical"
+	if _, err := l.Check(s, []string{}); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestRuleWarnIsAtLeast(t *testing.T) {
+	tests := []struct {
+		severity  string
+		threshold string
+		want      bool
+	}{
+		{SeverityError, SeverityError, true},
+		{SeverityWarning, SeverityError, false},
+		{SeverityInfo, SeverityError, false},
+		{SeverityError, SeverityWarning, true},
+		{SeverityWarning, SeverityWarning, true},
+		{SeverityInfo, SeverityWarning, false},
+		{SeverityInfo, SeverityInfo, true},
+	}
+	for _, tt := range tests {
+		w := RuleWarn{Severity: tt.severity}
+		if got := w.IsAtLeast(tt.threshold); got != tt.want {
+			t.Errorf("%s >= %s: got %v\nwant %v", tt.severity, tt.threshold, got, tt.want)
+		}
+	}
+}
+
+func TestNamingConvention(t *testing.T) {
+	tests := []struct {
+		rule NamingConvention
+		want []string
+	}{
+		{NamingConvention{Enabled: false, Table: `^[A-Z]+$`}, []string{}},
+		{NamingConvention{Enabled: true, Table: `^[a-z][a-z0-9_]*$`, Column: `^[a-z][a-z0-9_]*$`}, []string{}},
+		{NamingConvention{Enabled: true, Column: `^{table}_.*`}, []string{
+			"table_a.column_a1", "table_a.column_a2", "table_b.column_b1", "table_b.column_b2", "table_c.column_c1", "table_c.column_c2", "table_c.column_c3", "table_c.column_c4",
+		}},
+		{NamingConvention{Enabled: true, Column: `^column_[a-z]\d$`, Exclude: []string{"column_b1", "table_c.*"}}, []string{}},
+		{NamingConvention{Enabled: true, Index: `^idx_{table}_{columns}$`}, []string{"table_a.a2_idx"}},
+		{NamingConvention{Enabled: true, Index: `.*_idx`, ForeignKey: `^fk_{table}_{parent_table}$`, Unique: `^{table}_{columns}_key$`}, []string{"table_a.a1_b1_fk", "table_a.a1_unique"}},
+		{NamingConvention{Enabled: true, ForeignKey: `^{columns}_{parent_columns}_fk$`}, []string{"table_a.a1_b1_fk"}},
+		{NamingConvention{Enabled: true, TableForm: TableFormPlural}, []string{"table_a", "table_b", "table_c"}},
+		{NamingConvention{Enabled: true, TableForm: TableFormSingular, Exclude: []string{"table_c"}}, []string{}},
+	}
+	for i, tt := range tests {
+		if err := tt.rule.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		s := newTestSchema(t)
+		got := []string{}
+		for _, w := range tt.rule.Check(s, []string{}) {
+			got = append(got, w.Target)
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Errorf("TestNamingConvention(%d): %s", i, diff)
+		}
+	}
+}
+
+func TestNamingConventionWholeName(t *testing.T) {
+	tests := []struct {
+		table   string
+		pattern string
+		want    int
+	}{
+		{"user_accounts", `[a-z_]+`, 0},
+		{"UserAccounts", `[a-z_]+`, 1},
+		{"UserAccounts", `^[a-z_]+`, 1},
+		{"UserAccounts", `[a-z_]+|[A-Z][A-Za-z]+`, 0},
+	}
+	for i, tt := range tests {
+		s := &schema.Schema{Name: "testschema", Tables: []*schema.Table{{Name: tt.table}}}
+		r := NamingConvention{Enabled: true, Table: tt.pattern}
+		if got := r.Check(s, []string{}); len(got) != tt.want {
+			t.Errorf("TestNamingConventionWholeName(%d): got %v\nwant %v", i, got, tt.want)
+		}
+	}
+}
+
+func TestNamingConventionValidate(t *testing.T) {
+	tests := []struct {
+		rule    NamingConvention
+		wantErr bool
+	}{
+		{NamingConvention{Table: `^[a-z_]+$`, Index: `^idx_{table}_{columns}$`, TableForm: TableFormSingular}, false},
+		{NamingConvention{Table: `^[a-z_+$`}, true},
+		{NamingConvention{TableForm: "camel"}, true},
+	}
+	for i, tt := range tests {
+		if err := tt.rule.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("TestNamingConventionValidate(%d): got %v\nwant error %v", i, err, tt.wantErr)
+		}
+	}
+}
+
+func TestForeignKeyTypeMismatch(t *testing.T) {
+	tests := []struct {
+		driver          string
+		childType       string
+		parentType      string
+		equivalentTypes map[string][][]string
+		want            int
+	}{
+		{"postgres", "bigint", "bigint", nil, 0},
+		{"postgres", "integer", "bigint", nil, 1},
+		{"postgres", "int4", "integer", nil, 0},
+		{"postgres", "varchar(36)", "character varying(36)", nil, 0},
+		{"postgres", "varchar(36)", "character varying(255)", nil, 1},
+		{"postgres", "varchar(36)", "uuid", nil, 1},
+		{"postgres", "varchar(36)", "uuid", map[string][][]string{"postgres": {{"uuid", "varchar(36)"}}}, 0},
+		{"postgres", "varchar(36)", "uuid", map[string][][]string{"mysql": {{"uuid", "varchar(36)"}}}, 1},
+		{"mysql", "int(11)", "int", nil, 0},
+		{"mysql", "int(10) unsigned", "int", nil, 1},
+		{"mysql", "INT", "integer", nil, 0},
+		{"sqlite", "int", "integer", nil, 0},
+		{"bigquery", "int", "integer", nil, 1},
+	}
+	for i, tt := range tests {
+		s := newTestSchema(t)
+		s.Driver = &schema.Driver{Name: tt.driver}
+		s.Relations[0].Columns[0].Type = tt.childType
+		s.Relations[0].ParentColumns[0].Type = tt.parentType
+		r := ForeignKeyTypeMismatch{
+			Enabled:         true,
+			EquivalentTypes: tt.equivalentTypes,
+		}
+		if got := r.Check(s, []string{}); len(got) != tt.want {
+			t.Errorf("TestForeignKeyTypeMismatch(%d): got %v\nwant %v", i, got, tt.want)
+		}
+	}
+}
+
+func TestRedundantIndex(t *testing.T) {
+	idx := func(name, def string, columns ...string) *schema.Index {
+		return &schema.Index{Name: name, Def: def, Columns: columns}
+	}
+	tests := []struct {
+		indexes []*schema.Index
+		exclude []string
+		want    []string
+	}{
+		{
+			[]*schema.Index{
+				idx("a_pkey", "PRIMARY KEY (a)", "a"),
+				idx("b_idx", "CREATE INDEX b_idx ON t USING btree (b)", "b"),
+			},
+			[]string{},
+			[]string{},
+		},
+		{
+			[]*schema.Index{
+				idx("a_idx", "CREATE INDEX a_idx ON t USING btree (a)", "a"),
+				idx("a_b_idx", "CREATE INDEX a_b_idx ON t USING btree (a, b)", "a", "b"),
+				idx("b_a_idx", "CREATE INDEX b_a_idx ON t USING btree (b, a)", "b", "a"),
+			},
+			[]string{},
+			[]string{"table_a.a_idx: redundant index. [a_idx is a left-prefix of a_b_idx]"},
+		},
+		{
+			[]*schema.Index{
+				idx("a_key", "CREATE UNIQUE INDEX a_key ON t USING btree (a)", "a"),
+				idx("a_b_idx", "CREATE INDEX a_b_idx ON t USING btree (a, b)", "a", "b"),
+			},
+			[]string{},
+			[]string{},
+		},
+		{
+			[]*schema.Index{
+				idx("a_idx1", "CREATE INDEX a_idx1 ON t USING btree (a)", "a"),
+				idx("a_idx2", "CREATE INDEX a_idx2 ON t USING btree (a)", "a"),
+				idx("a_key", "CREATE UNIQUE INDEX a_key ON t USING btree (a)", "a"),
+			},
+			[]string{},
+			[]string{
+				"table_a.a_idx1: duplicate index. [a_idx1 is the same as a_key]",
+				"table_a.a_idx2: duplicate index. [a_idx2 is the same as a_idx1]",
+			},
+		},
+		{
+			[]*schema.Index{
+				idx("a_idx", "CREATE INDEX a_idx ON t USING btree (a)", "a"),
+				idx("a_gin", "CREATE INDEX a_gin ON t USING gin (a, b)", "a", "b"),
+				idx("a_partial", "CREATE INDEX a_partial ON t USING btree (a, b) WHERE (b IS NOT NULL)", "a", "b"),
+			},
+			[]string{},
+			[]string{},
+		},
+		{
+			[]*schema.Index{
+				idx("a_idx", "CREATE INDEX a_idx ON t USING btree (a)", "a"),
+				idx("a_b_idx", "CREATE INDEX a_b_idx ON t USING btree (a, b)", "a", "b"),
+			},
+			[]string{"a_idx"},
+			[]string{},
+		},
+	}
+	for i, tt := range tests {
+		s := newTestSchema(t)
+		s.Tables[0].Indexes = tt.indexes
+		r := RedundantIndex{
+			Enabled: true,
+			Exclude: tt.exclude,
+		}
+		got := []string{}
+		for _, w := range r.Check(s, []string{}) {
+			got = append(got, fmt.Sprintf("%s: %s", w.Target, w.Message))
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Errorf("TestRedundantIndex(%d): %s", i, diff)
+		}
+	}
+}
+
+func TestRequirePrimaryKey(t *testing.T) {
+	tests := []struct {
+		enabled     bool
+		lintExclude []string
+		exclude     []string
+		want        []string
+	}{
+		{true, []string{}, []string{}, []string{"table_a", "table_b"}},
+		{false, []string{}, []string{}, []string{}},
+		{true, []string{"table_a"}, []string{}, []string{"table_b"}},
+		{true, []string{}, []string{"table_b"}, []string{"table_a"}},
+	}
+	for i, tt := range tests {
+		s := newTestSchema(t)
+		s.Tables[2].Columns[0].PK = true
+		s.Tables = append(s.Tables, &schema.Table{
+			Name:    "view_a",
+			Type:    "VIEW",
+			Columns: []*schema.Column{{Name: "id"}},
+		})
+		r := RequirePrimaryKey{
+			Enabled: tt.enabled,
+			Exclude: tt.exclude,
+		}
+		got := []string{}
+		for _, w := range r.Check(s, tt.lintExclude) {
+			got = append(got, w.Target)
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Errorf("TestRequirePrimaryKey(%d): %s", i, diff)
+		}
+	}
+}
+
+func TestNullableIdentifierColumn(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		exclude  []string
+		want     []string
+	}{
+		{[]string{}, []string{}, []string{"table_c.id", "table_c.user_id"}},
+		{[]string{"*_id"}, []string{}, []string{"table_c.user_id"}},
+		{[]string{}, []string{"user_id"}, []string{"table_c.id"}},
+		{[]string{}, []string{"table_c"}, []string{}},
+	}
+	for i, tt := range tests {
+		s := newTestSchema(t)
+		// nullable but part of the relation
+		s.Tables[1].Columns[0].Name = "b_id"
+		tc := s.Tables[2]
+		tc.Columns = append(tc.Columns,
+			&schema.Column{Name: "id", Type: "bigint", Nullable: true},
+			&schema.Column{Name: "user_id", Type: "bigint", Nullable: true},
+			&schema.Column{Name: "post_id", Type: "bigint", Nullable: false},
+			&schema.Column{Name: "paid", Type: "bool", Nullable: true},
+		)
+		r := NullableIdentifierColumn{
+			Enabled:  true,
+			Patterns: tt.patterns,
+			Exclude:  tt.exclude,
+		}
+		got := []string{}
+		for _, w := range r.Check(s, []string{}) {
+			got = append(got, w.Target)
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Errorf("TestNullableIdentifierColumn(%d): %s", i, diff)
+		}
+	}
+}
+
+func TestReservedKeyword(t *testing.T) {
+	tests := []struct {
+		driver      string
+		dialects    []string
+		allDialects bool
+		exclude     []string
+		want        []string
+	}{
+		{"mysql", nil, false, nil, []string{
+			"order: table name `order` is a reserved keyword. [mysql]",
+			"order.key: column name `key` is a reserved keyword. [mysql]",
+		}},
+		{"postgres", nil, false, nil, []string{
+			"order: table name `order` is a reserved keyword. [postgres]",
+			"order.user: column name `user` is a reserved keyword. [postgres]",
+		}},
+		{"dynamodb", nil, false, nil, []string{}},
+		{"dynamodb", []string{"sqlite"}, false, nil, []string{
+			"order: table name `order` is a reserved keyword. [sqlite]",
+			"order.key: column name `key` is a reserved keyword. [sqlite]",
+		}},
+		{"mysql", nil, true, []string{"order.key", "order.user"}, []string{
+			"order: table name `order` is a reserved keyword. [bigquery, clickhouse, mssql, mysql, postgres, snowflake, spanner, sqlite]",
+		}},
+		{"mysql", nil, false, []string{"order"}, []string{}},
+	}
+	for i, tt := range tests {
+		s := &schema.Schema{
+			Name:   "testschema",
+			Driver: &schema.Driver{Name: tt.driver},
+			Tables: []*schema.Table{
+				{
+					Name: "order",
+					Columns: []*schema.Column{
+						{Name: "id"},
+						{Name: "key"},
+						{Name: "user"},
+					},
+				},
+			},
+		}
+		r := ReservedKeyword{
+			Enabled:     true,
+			Dialects:    tt.dialects,
+			AllDialects: tt.allDialects,
+			Exclude:     tt.exclude,
+		}
+		if err := r.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, w := range r.Check(s, []string{}) {
+			got = append(got, fmt.Sprintf("%s: %s", w.Target, w.Message))
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Errorf("TestReservedKeyword(%d): %s", i, diff)
+		}
+	}
+	if err := (ReservedKeyword{Dialects: []string{"oracle"}}).Validate(); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestCyclicRelations(t *testing.T) {
+	tests := []struct {
+		allowSelfReference bool
+		allow              []string
+		exclude            []string
+		want               []string
+	}{
+		{false, nil, nil, []string{
+			"a (a,b): tables reference each other cyclically. [a, b]",
+			"categories (categories): tables reference each other cyclically. [categories]",
+		}},
+		{true, nil, nil, []string{
+			"a (a,b): tables reference each other cyclically. [a, b]",
+		}},
+		{true, []string{"a"}, nil, []string{
+			"a (a,b): tables reference each other cyclically. [a, b]",
+		}},
+		{true, []string{"a", "b"}, nil, []string{}},
+		{true, nil, []string{"b"}, []string{}},
+	}
+	for i, tt := range tests {
+		s := &schema.Schema{Name: "testschema"}
+		for _, n := range []string{"a", "b", "categories", "c"} {
+			s.Tables = append(s.Tables, &schema.Table{Name: n})
+		}
+		for _, rel := range [][2]int{{0, 1}, {1, 0}, {2, 2}, {3, 0}} {
+			s.Relations = append(s.Relations, &schema.Relation{Table: s.Tables[rel[0]], ParentTable: s.Tables[rel[1]]})
+		}
+		r := CyclicRelations{
+			Enabled:            true,
+			AllowSelfReference: tt.allowSelfReference,
+			Allow:              tt.allow,
+		}
+		got := []string{}
+		for _, w := range r.Check(s, tt.exclude) {
+			got = append(got, fmt.Sprintf("%s (%s): %s", w.Target, w.Discriminator, w.Message))
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Errorf("TestCyclicRelations(%d): %s", i, diff)
+		}
+	}
+}
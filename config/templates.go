@@ -38,3 +38,19 @@ type Mermaid struct {
 	Schema string `yaml:"schema,omitempty"`
 	Table  string `yaml:"table,omitempty"`
 }
+
+// Files return the paths of the template files
+func (t Templates) Files() []string {
+	files := []string{}
+	for _, f := range []string{
+		t.MD.Index, t.MD.Table, t.MD.Viewpoint, t.MD.Enum,
+		t.Dot.Schema, t.Dot.Table,
+		t.PUML.Schema, t.PUML.Table,
+		t.Mermaid.Schema, t.Mermaid.Table,
+	} {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
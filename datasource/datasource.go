@@ -56,6 +56,15 @@ func Analyze(dsn config.DSN) (_ *schema.Schema, err error) {
 	if strings.HasPrefix(urlstr, "json://") {
 		return AnalyzeJSON(urlstr)
 	}
+	if strings.HasPrefix(urlstr, "sql://") {
+		return AnalyzeSQL(urlstr)
+	}
+	if strings.HasPrefix(urlstr, "migrations://") {
+		return AnalyzeMigrations(urlstr)
+	}
+	if strings.HasPrefix(urlstr, "dbml://") {
+		return AnalyzeDBML(urlstr)
+	}
 	if strings.HasPrefix(urlstr, "bq://") || strings.HasPrefix(urlstr, "bigquery://") {
 		return AnalyzeBigquery(urlstr)
 	}
@@ -304,3 +313,48 @@ func AnalyzeWithExtDriver(urlstr string) (*schema.Schema, error) {
 	}
 	return s, nil
 }
+
+// SourceFiles return the local files and directories that the schema is analyzed from ( `json://`, `sql://`, `migrations://` and `dbml://` ).
+// It returns nil for databases.
+func SourceFiles(dsn config.DSN) ([]string, error) {
+	urlstr := dsn.URL
+	switch {
+	case strings.HasPrefix(urlstr, "json://"):
+		return []string{strings.TrimPrefix(urlstr, "json://")}, nil
+	case strings.HasPrefix(urlstr, "dbml://"):
+		path, _, err := splitPathAndQuery(strings.TrimPrefix(urlstr, "dbml://"))
+		if err != nil {
+			return nil, err
+		}
+		return []string{path}, nil
+	case strings.HasPrefix(urlstr, "sql://"):
+		path, _, err := splitPathAndQuery(strings.TrimPrefix(urlstr, "sql://"))
+		if err != nil {
+			return nil, err
+		}
+		files, err := sqlFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+			// watch the directory to detect added or removed files
+			files = append([]string{path}, files...)
+		}
+		return files, nil
+	case strings.HasPrefix(urlstr, "migrations://"):
+		dir, _, err := splitPathAndQuery(strings.TrimPrefix(urlstr, "migrations://"))
+		if err != nil {
+			return nil, err
+		}
+		migrations, err := migrationFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		files := []string{dir}
+		for _, m := range migrations {
+			files = append(files, m.path)
+		}
+		return files, nil
+	}
+	return nil, nil
+}
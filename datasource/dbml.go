@@ -0,0 +1,38 @@
+package datasource
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/dbml"
+	"github.com/k1LoW/tbls/schema"
+)
+
+// AnalyzeDBML analyze `dbml://`
+func AnalyzeDBML(urlstr string) (_ *schema.Schema, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	path, values, err := splitPathAndQuery(strings.TrimPrefix(urlstr, "dbml://"))
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	s, err := dbml.Parse(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if name := values.Get("name"); name != "" {
+		s.Name = name
+	}
+	if s.Name == "" {
+		s.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return s, nil
+}
@@ -0,0 +1,40 @@
+package datasource
+
+import (
+	"testing"
+
+	"github.com/k1LoW/tbls/config"
+)
+
+func TestAnalyzeDBML(t *testing.T) {
+	tests := []struct {
+		dsn        string
+		wantName   string
+		wantTables int
+		wantErr    bool
+	}{
+		{"dbml://../testdata/dbml/blog.dbml", "blog", 3, false},
+		{"dbml://../testdata/dbml/blog.dbml?name=testdb", "testdb", 3, false},
+		{"dbml://../testdata/dbml/notfound.dbml", "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dsn, func(t *testing.T) {
+			s, err := Analyze(config.DSN{URL: tt.dsn})
+			if err != nil {
+				if !tt.wantErr {
+					t.Error(err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Error("want error")
+			}
+			if s.Name != tt.wantName {
+				t.Errorf("got %v\nwant %v", s.Name, tt.wantName)
+			}
+			if len(s.Tables) != tt.wantTables {
+				t.Errorf("got %v\nwant %v", len(s.Tables), tt.wantTables)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package datasource
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/drivers/sqlite"
+	"github.com/k1LoW/tbls/schema"
+)
+
+var (
+	// Flyway: V1.1__create_users.sql, R__create_views.sql ( U1.1__*.sql is undo migration )
+	reFlyway = regexp.MustCompile(`^([VUR])([0-9]+(?:[._][0-9]+)*)?__.+\.sql$`)
+	// golang-migrate: 000001_create_users.up.sql, 000001_create_users.down.sql
+	reGolangMigrate = regexp.MustCompile(`^([0-9]+)_.+\.(up|down)\.sql$`)
+	// goose: 20230101000000_create_users.sql, 00001_create_users.sql
+	reGoose       = regexp.MustCompile(`^([0-9]+)_.+\.sql$`)
+	reGooseMarker = regexp.MustCompile(`(?i)^--\s*\+goose\s+(up|down)\b`)
+)
+
+// migration is a migration file to be applied
+type migration struct {
+	path string
+	// version is the version segments of the migration. nil means that the file has no version (lexical order)
+	version []string
+	// repeatable is Flyway repeatable migration. It is applied after all versioned migrations
+	repeatable bool
+	// goose is whether the file has goose annotations ( -- +goose Up / -- +goose Down )
+	goose bool
+}
+
+// AnalyzeMigrations analyze `migrations://`
+func AnalyzeMigrations(urlstr string) (_ *schema.Schema, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	dir, values, err := splitPathAndQuery(strings.TrimPrefix(urlstr, "migrations://"))
+	if err != nil {
+		return nil, err
+	}
+	driver := values.Get("driver")
+	if driver == "" {
+		driver = "sqlite"
+	}
+	if driver != "sqlite" && driver != "sqlite3" {
+		return nil, fmt.Errorf("unsupported driver for migrations://: %s", driver)
+	}
+	migrations, err := migrationFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	name := values.Get("name")
+	if name == "" {
+		name = schemaNameFromPath(dir)
+	}
+
+	// Each connection to `:memory:` opens a distinct database, so use shared cache in-memory database
+	// to share it between the connections used by the driver.
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:tbls_migrations_%d?mode=memory&cache=shared", time.Now().UnixNano()))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	// The in-memory database is deleted when the last connection is closed
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	for _, m := range migrations {
+		q, err := m.up()
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(q) == "" {
+			continue
+		}
+		if _, err := conn.ExecContext(ctx, q); err != nil {
+			return nil, fmt.Errorf("failed to apply %s: %w", m.path, err)
+		}
+	}
+
+	s := &schema.Schema{
+		Name: name,
+	}
+	if err := sqlite.New(db).Analyze(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// migrationFiles return migration files in the directory in the order to be applied
+func migrationFiles(dir string) ([]*migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	migrations := []*migration{}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+			continue
+		}
+		n := e.Name()
+		m := &migration{path: filepath.Join(dir, n)}
+		switch {
+		case reFlyway.MatchString(n):
+			matches := reFlyway.FindStringSubmatch(n)
+			switch matches[1] {
+			case "U":
+				continue
+			case "R":
+				m.repeatable = true
+			default:
+				m.version = splitVersion(matches[2])
+			}
+		case reGolangMigrate.MatchString(n):
+			matches := reGolangMigrate.FindStringSubmatch(n)
+			if matches[2] == "down" {
+				continue
+			}
+			m.version = splitVersion(matches[1])
+		case reGoose.MatchString(n):
+			m.version = splitVersion(reGoose.FindStringSubmatch(n)[1])
+			m.goose = true
+		}
+		migrations = append(migrations, m)
+	}
+	if len(migrations) == 0 {
+		return nil, fmt.Errorf("no migration files found: %s", dir)
+	}
+	sort.SliceStable(migrations, func(i, j int) bool {
+		a, b := migrations[i], migrations[j]
+		if a.repeatable != b.repeatable {
+			return !a.repeatable
+		}
+		if a.version != nil && b.version != nil {
+			if c := compareVersion(a.version, b.version); c != 0 {
+				return c < 0
+			}
+		}
+		return filepath.Base(a.path) < filepath.Base(b.path)
+	})
+	return migrations, nil
+}
+
+// up return the SQL to apply the migration
+func (m *migration) up() (string, error) {
+	b, err := os.ReadFile(filepath.Clean(m.path))
+	if err != nil {
+		return "", err
+	}
+	if !m.goose {
+		return string(b), nil
+	}
+	up := new(strings.Builder)
+	in := false
+	// markers can follow blank lines or header comments, so detect them line by line
+	marked := false
+	scanner := bufio.NewScanner(strings.NewReader(string(b)))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(b)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if matches := reGooseMarker.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
+			in = strings.EqualFold(matches[1], "up")
+			marked = true
+			continue
+		}
+		if in {
+			up.WriteString(line)
+			up.WriteString("\n")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	// files without goose annotations are applied as is
+	if !marked {
+		return string(b), nil
+	}
+	return up.String(), nil
+}
+
+func splitVersion(v string) []string {
+	return strings.FieldsFunc(v, func(r rune) bool {
+		return r == '.' || r == '_'
+	})
+}
+
+// compareVersion compare version segments numerically
+func compareVersion(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		x, y := strings.TrimLeft(a[i], "0"), strings.TrimLeft(b[i], "0")
+		if len(x) != len(y) {
+			if len(x) < len(y) {
+				return -1
+			}
+			return 1
+		}
+		if c := strings.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+	return len(a) - len(b)
+}
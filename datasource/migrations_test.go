@@ -0,0 +1,72 @@
+package datasource
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/tbls/config"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestAnalyzeMigrations(t *testing.T) {
+	tests := []struct {
+		dsn           string
+		wantName      string
+		wantTables    []string
+		wantRelations int
+		wantErr       bool
+	}{
+		{"migrations://../testdata/migrations/flyway?driver=sqlite", "flyway", []string{"users", "posts", "post_titles"}, 1, false},
+		{"migrations://../testdata/migrations/golang-migrate?driver=sqlite&name=testdb", "testdb", []string{"users", "posts"}, 1, false},
+		{"migrations://../testdata/migrations/goose", "goose", []string{"users", "posts", "comments"}, 2, false},
+		{"migrations://../testdata/migrations/goose?driver=postgres", "", nil, 0, true},
+		{"migrations://../testdata/migrations/notfound", "", nil, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dsn, func(t *testing.T) {
+			s, err := Analyze(config.DSN{URL: tt.dsn})
+			if err != nil {
+				if !tt.wantErr {
+					t.Error(err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Error("want error")
+			}
+			if s.Name != tt.wantName {
+				t.Errorf("got %v\nwant %v", s.Name, tt.wantName)
+			}
+			got := []string{}
+			for _, tbl := range s.Tables {
+				got = append(got, tbl.Name)
+			}
+			if diff := cmp.Diff(got, tt.wantTables); diff != "" {
+				t.Error(diff)
+			}
+			if len(s.Relations) != tt.wantRelations {
+				t.Errorf("got %v\nwant %v", len(s.Relations), tt.wantRelations)
+			}
+		})
+	}
+}
+
+func TestMigrationFiles(t *testing.T) {
+	got := []string{}
+	migrations, err := migrationFiles("../testdata/migrations/flyway")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range migrations {
+		got = append(got, m.path)
+	}
+	want := []string{
+		"../testdata/migrations/flyway/V1__create_users.sql",
+		"../testdata/migrations/flyway/V2__create_posts.sql",
+		"../testdata/migrations/flyway/V10__add_posts_body.sql",
+		"../testdata/migrations/flyway/R__create_views.sql",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,89 @@
+package datasource
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/ddl"
+	"github.com/k1LoW/tbls/schema"
+)
+
+// AnalyzeSQL analyze `sql://`
+func AnalyzeSQL(urlstr string) (_ *schema.Schema, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	path, values, err := splitPathAndQuery(strings.TrimPrefix(urlstr, "sql://"))
+	if err != nil {
+		return nil, err
+	}
+	dialect := values.Get("dialect")
+	if dialect == "" {
+		return nil, fmt.Errorf("dialect is required: sql://%s?dialect=postgres", path)
+	}
+	files, err := sqlFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	name := values.Get("name")
+	if name == "" {
+		name = schemaNameFromPath(path)
+	}
+	b, err := ddl.NewBuilder(name, dialect)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		src, err := os.ReadFile(filepath.Clean(f))
+		if err != nil {
+			return nil, err
+		}
+		if err := b.Parse(string(src)); err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+	}
+	return b.Build()
+}
+
+// sqlFiles return SQL files sorted by name. The path can be a file, a directory or a glob pattern.
+func sqlFiles(path string) ([]string, error) {
+	fi, err := os.Stat(path)
+	if err == nil && fi.IsDir() {
+		path = filepath.Join(path, "*.sql")
+	}
+	files, err := filepath.Glob(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no SQL files found: %s", path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func schemaNameFromPath(path string) string {
+	dir := path
+	if strings.ContainsAny(filepath.Base(path), "*?[") || strings.HasSuffix(path, ".sql") {
+		dir = filepath.Dir(path)
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return filepath.Base(dir)
+	}
+	return filepath.Base(abs)
+}
+
+func splitPathAndQuery(str string) (string, url.Values, error) {
+	path, query, _ := strings.Cut(str, "?")
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, values, nil
+}
@@ -0,0 +1,83 @@
+package datasource
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/tbls/config"
+)
+
+func TestAnalyzeSQL(t *testing.T) {
+	tests := []struct {
+		dsn           string
+		wantName      string
+		wantTables    int
+		wantRelations int
+		wantErr       bool
+	}{
+		{"sql://../testdata/ddl/postgres?dialect=postgres", "postgres", 3, 3, false},
+		{"sql://../testdata/ddl/postgres/*.sql?dialect=postgres&name=testdb", "testdb", 3, 3, false},
+		{"sql://../testdata/ddl/postgres/001_create_users.sql?dialect=postgres", "postgres", 1, 0, false},
+		{"sql://../testdata/ddl/postgres", "", 0, 0, true},
+		{"sql://../testdata/ddl/notfound/*.sql?dialect=postgres", "", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dsn, func(t *testing.T) {
+			s, err := Analyze(config.DSN{URL: tt.dsn})
+			if err != nil {
+				if !tt.wantErr {
+					t.Error(err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Error("want error")
+			}
+			if s.Name != tt.wantName {
+				t.Errorf("got %v\nwant %v", s.Name, tt.wantName)
+			}
+			if len(s.Tables) != tt.wantTables {
+				t.Errorf("got %v\nwant %v", len(s.Tables), tt.wantTables)
+			}
+			if len(s.Relations) != tt.wantRelations {
+				t.Errorf("got %v\nwant %v", len(s.Relations), tt.wantRelations)
+			}
+		})
+	}
+}
+
+func TestSourceFiles(t *testing.T) {
+	tests := []struct {
+		dsn  string
+		want []string
+	}{
+		{"json://../testdata/testdb.json", []string{"../testdata/testdb.json"}},
+		{"dbml://../testdata/dbml/blog.dbml?name=blog", []string{"../testdata/dbml/blog.dbml"}},
+		{"sql://../testdata/ddl/postgres/001_create_users.sql?dialect=postgres", []string{"../testdata/ddl/postgres/001_create_users.sql"}},
+		{"sql://../testdata/ddl/postgres?dialect=postgres", []string{
+			"../testdata/ddl/postgres",
+			"../testdata/ddl/postgres/001_create_users.sql",
+			"../testdata/ddl/postgres/002_create_posts.sql",
+			"../testdata/ddl/postgres/003_add_comments.sql",
+		}},
+		{"migrations://../testdata/migrations/flyway", []string{
+			"../testdata/migrations/flyway",
+			"../testdata/migrations/flyway/V1__create_users.sql",
+			"../testdata/migrations/flyway/V2__create_posts.sql",
+			"../testdata/migrations/flyway/V10__add_posts_body.sql",
+			"../testdata/migrations/flyway/R__create_views.sql",
+		}},
+		{"pg://dbuser:dbpass@localhost:55432/testdb", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dsn, func(t *testing.T) {
+			got, err := SourceFiles(config.DSN{URL: tt.dsn})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
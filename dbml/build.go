@@ -0,0 +1,284 @@
+package dbml
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/k1LoW/tbls/schema"
+)
+
+const defaultDriverName = "dbml"
+
+// build return the schema from parsed elements
+func (p *parser) build() (*schema.Schema, error) {
+	s := &schema.Schema{
+		Name:  p.projectName,
+		Desc:  p.note,
+		Enums: p.enums,
+		Driver: &schema.Driver{
+			Name: driverName(p.databaseType),
+			Meta: &schema.DriverMeta{},
+		},
+	}
+
+	aliases := map[string]string{}
+	for _, t := range p.tables {
+		if t.alias != "" {
+			aliases[t.alias] = t.name
+		}
+		s.Tables = append(s.Tables, buildTable(t))
+	}
+
+	findTable := func(e endpoint) (*schema.Table, error) {
+		name := e.table
+		if n, ok := aliases[name]; ok {
+			name = n
+		}
+		t, err := s.FindTableByName(name)
+		if err != nil {
+			// `public.users` refers `users`
+			if _, n, ok := strings.Cut(name, "."); ok {
+				if t, err := s.FindTableByName(n); err == nil {
+					return t, nil
+				}
+			}
+			return nil, fmt.Errorf("line %d: %w", e.line, err)
+		}
+		return t, nil
+	}
+	findColumns := func(t *schema.Table, e endpoint) ([]*schema.Column, error) {
+		columns := []*schema.Column{}
+		for _, cn := range e.columns {
+			c, err := t.FindColumnByName(cn)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", e.line, err)
+			}
+			columns = append(columns, c)
+		}
+		return columns, nil
+	}
+
+	for _, r := range p.refs {
+		child, parent := r.from, r.to
+		if r.op == "<" {
+			child, parent = r.to, r.from
+		}
+		ct, err := findTable(child)
+		if err != nil {
+			return nil, err
+		}
+		pt, err := findTable(parent)
+		if err != nil {
+			return nil, err
+		}
+		ccs, err := findColumns(ct, child)
+		if err != nil {
+			return nil, err
+		}
+		pcs, err := findColumns(pt, parent)
+		if err != nil {
+			return nil, err
+		}
+		def := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)", strings.Join(child.columns, ", "), pt.Name, strings.Join(parent.columns, ", "))
+		for _, st := range r.settings {
+			if (st.key == "delete" || st.key == "update") && len(st.value) > 0 {
+				def += fmt.Sprintf(" ON %s %s", strings.ToUpper(st.key), strings.ToUpper(joinValues(st.value)))
+			}
+		}
+		rel := &schema.Relation{
+			Table:         ct,
+			Columns:       ccs,
+			ParentTable:   pt,
+			ParentColumns: pcs,
+			Def:           def,
+		}
+		rel.Cardinality, rel.ParentCardinality = cardinalities(r.op, ccs)
+		s.Relations = append(s.Relations, rel)
+
+		tn := ct.Name
+		ptn := pt.Name
+		ct.Constraints = append(ct.Constraints, &schema.Constraint{
+			Name:              fmt.Sprintf("%s_%s_fkey", unqualified(ct.Name), strings.Join(child.columns, "_")),
+			Type:              schema.TypeFK,
+			Def:               def,
+			Table:             &tn,
+			ReferencedTable:   &ptn,
+			Columns:           child.columns,
+			ReferencedColumns: parent.columns,
+		})
+	}
+
+	for _, g := range p.groups {
+		tables := []string{}
+		for _, tn := range g.tables {
+			t, err := findTable(endpoint{table: tn})
+			if err != nil {
+				return nil, err
+			}
+			tables = append(tables, t.Name)
+		}
+		// the group is also kept as ViewpointGroup so that output/dbml writes it back as TableGroup
+		s.Viewpoints = append(s.Viewpoints, &schema.Viewpoint{
+			Name:   g.name,
+			Desc:   g.note,
+			Tables: tables,
+			Groups: []*schema.ViewpointGroup{
+				{
+					Name:   g.name,
+					Desc:   g.note,
+					Tables: tables,
+				},
+			},
+		})
+	}
+
+	if err := s.Repair(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func buildTable(t *table) *schema.Table {
+	tt := &schema.Table{
+		Name:    t.name,
+		Type:    "table",
+		Comment: t.note,
+	}
+	name := t.name
+	pks := []string{}
+	for _, c := range t.columns {
+		col := &schema.Column{
+			Name:     c.name,
+			Type:     c.typ,
+			Nullable: !c.notNull && !c.pk,
+			Default:  c.def,
+			Comment:  c.note,
+			ExtraDef: c.extraDef,
+		}
+		tt.Columns = append(tt.Columns, col)
+		if c.pk {
+			pks = append(pks, c.name)
+		}
+	}
+	for _, i := range t.indexes {
+		if i.pk {
+			pks = append(pks, i.columns...)
+			for _, cn := range i.columns {
+				if c, err := tt.FindColumnByName(cn); err == nil {
+					c.Nullable = false
+				}
+			}
+		}
+	}
+	if len(pks) > 0 {
+		def := fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pks, ", "))
+		cname := fmt.Sprintf("%s_pkey", unqualified(t.name))
+		tt.Constraints = append(tt.Constraints, &schema.Constraint{
+			Name:    cname,
+			Type:    "PRIMARY KEY",
+			Def:     def,
+			Table:   &name,
+			Columns: pks,
+		})
+		tt.Indexes = append(tt.Indexes, &schema.Index{
+			Name:    cname,
+			Def:     def,
+			Table:   &name,
+			Columns: pks,
+		})
+	}
+	for _, c := range t.columns {
+		if !c.unique {
+			continue
+		}
+		def := fmt.Sprintf("UNIQUE (%s)", c.name)
+		cname := fmt.Sprintf("%s_%s_key", unqualified(t.name), c.name)
+		tt.Constraints = append(tt.Constraints, &schema.Constraint{
+			Name:    cname,
+			Type:    "UNIQUE",
+			Def:     def,
+			Table:   &name,
+			Columns: []string{c.name},
+		})
+		tt.Indexes = append(tt.Indexes, &schema.Index{
+			Name:    cname,
+			Def:     def,
+			Table:   &name,
+			Columns: []string{c.name},
+		})
+	}
+	for _, i := range t.indexes {
+		if i.pk {
+			continue
+		}
+		iname := i.name
+		if iname == "" {
+			iname = fmt.Sprintf("%s_%s_idx", unqualified(t.name), strings.Join(i.columns, "_"))
+		}
+		def := "CREATE INDEX"
+		if i.unique {
+			def = "CREATE UNIQUE INDEX"
+		}
+		def += fmt.Sprintf(" %s ON %s", iname, t.name)
+		if i.typ != "" {
+			def += fmt.Sprintf(" USING %s", i.typ)
+		}
+		def += fmt.Sprintf(" (%s)", strings.Join(i.columns, ", "))
+		tt.Indexes = append(tt.Indexes, &schema.Index{
+			Name:    iname,
+			Def:     def,
+			Table:   &name,
+			Columns: i.columns,
+			Comment: i.note,
+		})
+	}
+	return tt
+}
+
+// cardinalities return the cardinalities of child and parent by the relationship operator
+func cardinalities(op string, columns []*schema.Column) (schema.Cardinality, schema.Cardinality) {
+	parent := schema.ExactlyOne
+	for _, c := range columns {
+		if c.Nullable {
+			parent = schema.ZeroOrOne
+		}
+	}
+	switch op {
+	case "-":
+		return schema.ZeroOrOne, parent
+	case "<>":
+		return schema.ZeroOrMore, schema.ZeroOrMore
+	default:
+		return schema.ZeroOrMore, parent
+	}
+}
+
+func driverName(databaseType string) string {
+	switch strings.ToLower(databaseType) {
+	case "postgresql", "postgres":
+		return "postgres"
+	case "mysql":
+		return "mysql"
+	case "sqlite":
+		return "sqlite"
+	case "sql server", "sqlserver", "mssql":
+		return "sqlserver"
+	default:
+		return defaultDriverName
+	}
+}
+
+func unqualified(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+func joinValues(tokens []Token) string {
+	vs := []string{}
+	for _, t := range tokens {
+		vs = append(vs, t.Value)
+	}
+	return strings.Join(vs, " ")
+}
@@ -0,0 +1,218 @@
+package dbml
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenKind is the kind of token
+type TokenKind int
+
+const (
+	// TokenIdent is keyword or unquoted identifier
+	TokenIdent TokenKind = iota
+	// TokenQuotedIdent is double-quoted identifier ("***")
+	TokenQuotedIdent
+	// TokenString is string literal ('***' or '''***''')
+	TokenString
+	// TokenExpr is expression (`***`)
+	TokenExpr
+	// TokenNumber is numeric literal
+	TokenNumber
+	// TokenColor is color code (#***)
+	TokenColor
+	// TokenSymbol is operator or punctuation
+	TokenSymbol
+	// TokenNewline is line break. It is significant in DBML because elements are separated by lines
+	TokenNewline
+)
+
+// Token is the token of DBML
+type Token struct {
+	Kind  TokenKind
+	Value string
+	Line  int
+}
+
+// Is return whether the token is the keyword (case-insensitive)
+func (t Token) Is(keywords ...string) bool {
+	if t.Kind != TokenIdent {
+		return false
+	}
+	for _, k := range keywords {
+		if strings.EqualFold(t.Value, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSymbol return whether the token is the symbol
+func (t Token) IsSymbol(s string) bool {
+	return t.Kind == TokenSymbol && t.Value == s
+}
+
+// IsName return whether the token can be a name
+func (t Token) IsName() bool {
+	return t.Kind == TokenIdent || t.Kind == TokenQuotedIdent
+}
+
+// Tokenize split DBML into tokens. Comments are skipped.
+func Tokenize(src string) ([]Token, error) {
+	tokens := []Token{}
+	line := 1
+	i := 0
+	for i < len(src) {
+		r, width := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case r == '\n':
+			tokens = append(tokens, Token{Kind: TokenNewline, Value: "\n", Line: line})
+			line++
+			i += width
+		case unicode.IsSpace(r):
+			i += width
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case strings.HasPrefix(src[i:], "'''"):
+			end := strings.Index(src[i+3:], "'''")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			tokens = append(tokens, Token{Kind: TokenString, Value: dedent(src[i+3 : i+3+end]), Line: line})
+			line += strings.Count(src[i:i+3+end], "\n")
+			i += end + 6
+		case r == '\'' || r == '"' || r == '`':
+			value, end, ok := scanQuoted(src, i)
+			if !ok {
+				return nil, fmt.Errorf("line %d: unterminated %c", line, r)
+			}
+			kind := TokenString
+			switch r {
+			case '"':
+				kind = TokenQuotedIdent
+			case '`':
+				kind = TokenExpr
+			}
+			tokens = append(tokens, Token{Kind: kind, Value: value, Line: line})
+			line += strings.Count(src[i:end], "\n")
+			i = end
+		case r == '#':
+			start := i
+			i++
+			for i < len(src) && isHex(src[i]) {
+				i++
+			}
+			tokens = append(tokens, Token{Kind: TokenColor, Value: src[start:i], Line: line})
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(src) && isDigit(src[i+1]) && !prevIsValue(tokens)):
+			start := i
+			i++
+			for i < len(src) && (isDigit(src[i]) || src[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, Token{Kind: TokenNumber, Value: src[start:i], Line: line})
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(src) {
+				r, width := utf8.DecodeRuneInString(src[i:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				i += width
+			}
+			tokens = append(tokens, Token{Kind: TokenIdent, Value: src[start:i], Line: line})
+		case strings.HasPrefix(src[i:], "<>"):
+			tokens = append(tokens, Token{Kind: TokenSymbol, Value: "<>", Line: line})
+			i += 2
+		default:
+			tokens = append(tokens, Token{Kind: TokenSymbol, Value: string(r), Line: line})
+			i += width
+		}
+	}
+	return tokens, nil
+}
+
+// prevIsValue return whether the last token is a value. It is used to distinguish `-` of one-to-one relationship from negative number.
+func prevIsValue(tokens []Token) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+	switch t := tokens[len(tokens)-1]; t.Kind {
+	case TokenIdent, TokenQuotedIdent, TokenNumber:
+		return true
+	case TokenSymbol:
+		return t.Value == ")"
+	}
+	return false
+}
+
+func scanQuoted(src string, start int) (string, int, bool) {
+	q := src[start]
+	b := new(strings.Builder)
+	i := start + 1
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '\\' && i+1 < len(src):
+			switch src[i+1] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(src[i+1])
+			}
+			i += 2
+			continue
+		case c == q:
+			return b.String(), i + 1, true
+		}
+		b.WriteByte(c)
+		i++
+	}
+	return "", len(src), false
+}
+
+// dedent remove the common indentation of multi-line string
+func dedent(s string) string {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "\r"), "\n")
+	lines := strings.Split(strings.TrimRight(s, " \t\r\n"), "\n")
+	indent := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		n := len(l) - len(strings.TrimLeft(l, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i, l := range lines {
+		if len(l) >= indent && indent > 0 {
+			lines[i] = l[indent:]
+		}
+		lines[i] = strings.TrimRight(lines[i], "\r")
+	}
+	return strings.ReplaceAll(strings.Join(lines, "\n"), `\'''`, "'''")
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isHex(c byte) bool {
+	return isDigit(c) || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
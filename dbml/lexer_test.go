@@ -0,0 +1,44 @@
+package dbml
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{
+			"Ref: a.id - b.id // one-to-one",
+			[]string{"Ref", ":", "a", ".", "id", "-", "b", ".", "id"},
+		},
+		{
+			"score int [default: -1, headercolor: #3498DB]",
+			[]string{"score", "int", "[", "default", ":", "-1", ",", "headercolor", ":", "#3498DB", "]"},
+		},
+		{
+			"Note: '''\n  line1\n    line2\n  ''' /* comment */ `now()` \"in review\" 'it\\'s'",
+			[]string{"Note", ":", "line1\n  line2", "now()", "in review", "it's"},
+		},
+		{
+			"a.id <> b.id\n",
+			[]string{"a", ".", "id", "<>", "b", ".", "id", "\n"},
+		},
+	}
+	for _, tt := range tests {
+		tokens, err := Tokenize(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, tok := range tokens {
+			got = append(got, tok.Value)
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
@@ -0,0 +1,696 @@
+package dbml
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/schema"
+)
+
+// setting is the setting in brackets such as `[pk, note: 'comment']`
+type setting struct {
+	key   string
+	value []Token
+}
+
+type table struct {
+	name    string
+	alias   string
+	note    string
+	columns []*column
+	indexes []*index
+	line    int
+}
+
+type column struct {
+	name     string
+	typ      string
+	pk       bool
+	unique   bool
+	notNull  bool
+	def      sql.NullString
+	note     string
+	extraDef string
+}
+
+type index struct {
+	name    string
+	columns []string
+	pk      bool
+	unique  bool
+	typ     string
+	note    string
+}
+
+type endpoint struct {
+	table   string
+	columns []string
+	line    int
+}
+
+type ref struct {
+	from     endpoint
+	to       endpoint
+	op       string
+	settings []setting
+}
+
+type tableGroup struct {
+	name   string
+	note   string
+	tables []string
+}
+
+// parser is the parser of DBML
+type parser struct {
+	tokens []Token
+	pos    int
+
+	projectName  string
+	note         string
+	databaseType string
+	tables       []*table
+	enums        []*schema.Enum
+	refs         []*ref
+	groups       []*tableGroup
+}
+
+// Parse DBML and return schema
+func Parse(src string) (_ *schema.Schema, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	tokens, err := Tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.build()
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() Token {
+	if p.eof() {
+		return Token{Kind: TokenNewline}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() Token {
+	t := p.peek()
+	if !p.eof() {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) line() int {
+	if p.eof() {
+		if len(p.tokens) == 0 {
+			return 1
+		}
+		return p.tokens[len(p.tokens)-1].Line
+	}
+	return p.tokens[p.pos].Line
+}
+
+func (p *parser) errorf(format string, a ...any) error {
+	return fmt.Errorf("line %d: %s", p.line(), fmt.Sprintf(format, a...))
+}
+
+func (p *parser) skipNewlines() {
+	for !p.eof() && p.peek().Kind == TokenNewline {
+		p.next()
+	}
+}
+
+func (p *parser) expectSymbol(s string) error {
+	p.skipNewlines()
+	if !p.peek().IsSymbol(s) {
+		return p.errorf("expected '%s' but got '%s'", s, p.peek().Value)
+	}
+	p.next()
+	return nil
+}
+
+// name consumes a qualified name such as `schema.table`
+func (p *parser) name() ([]string, error) {
+	parts := []string{}
+	for {
+		t := p.peek()
+		if !t.IsName() {
+			return nil, p.errorf("expected name but got '%s'", t.Value)
+		}
+		parts = append(parts, p.next().Value)
+		if !p.peek().IsSymbol(".") {
+			return parts, nil
+		}
+		p.next()
+		if p.peek().IsSymbol("(") {
+			// composite columns of endpoint
+			p.pos--
+			return parts, nil
+		}
+	}
+}
+
+// skipBlock skips tokens until the end of the current line or the end of the following block
+func (p *parser) skipBlock() {
+	for !p.eof() && p.peek().Kind != TokenNewline && !p.peek().IsSymbol("{") {
+		p.next()
+	}
+	if !p.peek().IsSymbol("{") {
+		return
+	}
+	depth := 0
+	for !p.eof() {
+		t := p.next()
+		switch {
+		case t.IsSymbol("{"):
+			depth++
+		case t.IsSymbol("}"):
+			depth--
+			if depth == 0 {
+				return
+			}
+		}
+	}
+}
+
+func (p *parser) parse() error {
+	for {
+		p.skipNewlines()
+		if p.eof() {
+			return nil
+		}
+		t := p.peek()
+		var err error
+		switch {
+		case t.Is("Project"):
+			err = p.parseProject()
+		case t.Is("Table"):
+			err = p.parseTable()
+		case t.Is("Ref"):
+			err = p.parseRef()
+		case t.Is("Enum"):
+			err = p.parseEnum()
+		case t.Is("TableGroup"):
+			err = p.parseTableGroup()
+		default:
+			// Note, TablePartial, Records and so on
+			p.skipBlock()
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (p *parser) parseProject() error {
+	p.next() // Project
+	if p.peek().IsName() {
+		p.projectName = p.next().Value
+	}
+	if err := p.expectSymbol("{"); err != nil {
+		return err
+	}
+	for {
+		p.skipNewlines()
+		t := p.peek()
+		switch {
+		case p.eof():
+			return p.errorf("unterminated Project")
+		case t.IsSymbol("}"):
+			p.next()
+			return nil
+		case t.Is("Note"):
+			note, err := p.parseNote()
+			if err != nil {
+				return err
+			}
+			p.note = note
+		case t.Is("database_type"):
+			p.next()
+			if err := p.expectSymbol(":"); err != nil {
+				return err
+			}
+			p.databaseType = p.next().Value
+		default:
+			p.skipBlock()
+		}
+	}
+}
+
+// parseNote parses `Note: '...'` or `Note { '...' }`
+func (p *parser) parseNote() (string, error) {
+	p.next() // Note
+	if p.peek().IsSymbol(":") {
+		p.next()
+		t := p.next()
+		if t.Kind != TokenString {
+			return "", p.errorf("expected string but got '%s'", t.Value)
+		}
+		return t.Value, nil
+	}
+	if err := p.expectSymbol("{"); err != nil {
+		return "", err
+	}
+	p.skipNewlines()
+	t := p.next()
+	if t.Kind != TokenString {
+		return "", p.errorf("expected string but got '%s'", t.Value)
+	}
+	if err := p.expectSymbol("}"); err != nil {
+		return "", err
+	}
+	return t.Value, nil
+}
+
+// parseSettings parses `[setting, key: value]`
+func (p *parser) parseSettings() ([]setting, error) {
+	if !p.peek().IsSymbol("[") {
+		return nil, nil
+	}
+	p.next()
+	settings := []setting{}
+	for {
+		p.skipNewlines()
+		if p.eof() {
+			return nil, p.errorf("unterminated settings")
+		}
+		if p.peek().IsSymbol("]") {
+			p.next()
+			return settings, nil
+		}
+		words := []string{}
+		var value []Token
+		for !p.eof() && !p.peek().IsSymbol(",") && !p.peek().IsSymbol("]") {
+			t := p.next()
+			if t.Kind == TokenNewline {
+				continue
+			}
+			if t.IsSymbol(":") && value == nil {
+				value = []Token{}
+				continue
+			}
+			if value != nil {
+				value = append(value, t)
+				continue
+			}
+			words = append(words, strings.ToLower(t.Value))
+		}
+		if p.peek().IsSymbol(",") {
+			p.next()
+		}
+		settings = append(settings, setting{key: strings.Join(words, " "), value: value})
+	}
+}
+
+func (p *parser) parseTable() error {
+	p.next() // Table
+	line := p.line()
+	parts, err := p.name()
+	if err != nil {
+		return err
+	}
+	t := &table{name: strings.Join(parts, "."), line: line}
+	if p.peek().Is("as") {
+		p.next()
+		t.alias = p.next().Value
+	}
+	settings, err := p.parseSettings()
+	if err != nil {
+		return err
+	}
+	for _, s := range settings {
+		if s.key == "note" && len(s.value) > 0 {
+			t.note = s.value[0].Value
+		}
+	}
+	if err := p.expectSymbol("{"); err != nil {
+		return err
+	}
+	for {
+		p.skipNewlines()
+		tok := p.peek()
+		switch {
+		case p.eof():
+			return p.errorf("unterminated Table %s", t.name)
+		case tok.IsSymbol("}"):
+			p.next()
+			p.tables = append(p.tables, t)
+			return nil
+		case tok.Is("Note") && (p.peekAt(1).IsSymbol(":") || p.peekAt(1).IsSymbol("{")):
+			note, err := p.parseNote()
+			if err != nil {
+				return err
+			}
+			t.note = note
+		case tok.Is("Indexes") && p.peekAt(1).IsSymbol("{"):
+			indexes, err := p.parseIndexes()
+			if err != nil {
+				return err
+			}
+			t.indexes = append(t.indexes, indexes...)
+		case tok.Is("Checks") && p.peekAt(1).IsSymbol("{"), tok.IsSymbol("~"):
+			p.skipBlock()
+		default:
+			c, err := p.parseColumn(t)
+			if err != nil {
+				return err
+			}
+			t.columns = append(t.columns, c)
+		}
+	}
+}
+
+func (p *parser) peekAt(n int) Token {
+	i := p.pos + n
+	if i >= len(p.tokens) {
+		return Token{Kind: TokenNewline}
+	}
+	return p.tokens[i]
+}
+
+func (p *parser) parseColumn(t *table) (*column, error) {
+	if !p.peek().IsName() {
+		return nil, p.errorf("expected column name but got '%s'", p.peek().Value)
+	}
+	c := &column{name: p.next().Value}
+	// type
+	typ := new(strings.Builder)
+	depth := 0
+	for !p.eof() {
+		tok := p.peek()
+		if tok.Kind == TokenNewline || tok.IsSymbol("}") || (tok.IsSymbol("[") && depth == 0 && !p.peekAt(1).IsSymbol("]")) {
+			break
+		}
+		switch {
+		case tok.IsSymbol("("):
+			depth++
+		case tok.IsSymbol(")"):
+			depth--
+		}
+		p.next()
+		typ.WriteString(tok.Value)
+	}
+	c.typ = typ.String()
+	if c.typ == "" {
+		return nil, p.errorf("expected type of column %s", c.name)
+	}
+	settings, err := p.parseSettings()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range settings {
+		switch s.key {
+		case "pk", "primary key":
+			c.pk = true
+		case "unique":
+			c.unique = true
+		case "not null":
+			c.notNull = true
+		case "null":
+			c.notNull = false
+		case "increment":
+			c.extraDef = "auto_increment"
+		case "note":
+			if len(s.value) > 0 {
+				c.note = s.value[0].Value
+			}
+		case "default":
+			if len(s.value) > 0 {
+				c.def = sql.NullString{String: defaultValue(s.value), Valid: true}
+			}
+		case "ref":
+			r, err := p.inlineRef(t, c, s.value)
+			if err != nil {
+				return nil, err
+			}
+			p.refs = append(p.refs, r)
+		}
+	}
+	return c, nil
+}
+
+// inlineRef return the relationship of `ref: > table.column`
+func (p *parser) inlineRef(t *table, c *column, value []Token) (*ref, error) {
+	if len(value) < 2 || value[0].Kind != TokenSymbol {
+		return nil, p.errorf("invalid ref of column %s", c.name)
+	}
+	sub := &parser{tokens: value[1:]}
+	to, err := sub.endpoint()
+	if err != nil {
+		return nil, p.errorf("invalid ref of column %s: %s", c.name, err)
+	}
+	to.line = p.line()
+	return &ref{
+		from: endpoint{table: t.name, columns: []string{c.name}, line: p.line()},
+		to:   to,
+		op:   value[0].Value,
+	}, nil
+}
+
+func (p *parser) parseIndexes() ([]*index, error) {
+	p.next() // Indexes
+	if err := p.expectSymbol("{"); err != nil {
+		return nil, err
+	}
+	indexes := []*index{}
+	for {
+		p.skipNewlines()
+		tok := p.peek()
+		if p.eof() {
+			return nil, p.errorf("unterminated Indexes")
+		}
+		if tok.IsSymbol("}") {
+			p.next()
+			return indexes, nil
+		}
+		i := &index{}
+		if tok.IsSymbol("(") {
+			p.next()
+			for !p.eof() && !p.peek().IsSymbol(")") {
+				t := p.next()
+				if t.IsSymbol(",") || t.Kind == TokenNewline {
+					continue
+				}
+				i.columns = append(i.columns, t.Value)
+			}
+			if err := p.expectSymbol(")"); err != nil {
+				return nil, err
+			}
+		} else {
+			i.columns = append(i.columns, p.next().Value)
+		}
+		settings, err := p.parseSettings()
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range settings {
+			switch s.key {
+			case "pk":
+				i.pk = true
+			case "unique":
+				i.unique = true
+			case "name":
+				if len(s.value) > 0 {
+					i.name = s.value[0].Value
+				}
+			case "type":
+				if len(s.value) > 0 {
+					i.typ = s.value[0].Value
+				}
+			case "note":
+				if len(s.value) > 0 {
+					i.note = s.value[0].Value
+				}
+			}
+		}
+		indexes = append(indexes, i)
+	}
+}
+
+// endpoint parses `table.column`, `schema.table.column` or `table.(column1, column2)`
+func (p *parser) endpoint() (endpoint, error) {
+	e := endpoint{line: p.line()}
+	parts, err := p.name()
+	if err != nil {
+		return e, err
+	}
+	if p.peek().IsSymbol(".") && p.peekAt(1).IsSymbol("(") {
+		p.next()
+		p.next()
+		for !p.eof() && !p.peek().IsSymbol(")") {
+			t := p.next()
+			if t.IsSymbol(",") {
+				continue
+			}
+			e.columns = append(e.columns, t.Value)
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return e, err
+		}
+		e.table = strings.Join(parts, ".")
+		return e, nil
+	}
+	if len(parts) < 2 {
+		return e, p.errorf("expected table.column but got '%s'", strings.Join(parts, "."))
+	}
+	e.table = strings.Join(parts[:len(parts)-1], ".")
+	e.columns = []string{parts[len(parts)-1]}
+	return e, nil
+}
+
+func (p *parser) parseRef() error {
+	p.next() // Ref
+	if p.peek().IsName() {
+		p.next() // name of relationship
+	}
+	if p.peek().IsSymbol(":") {
+		p.next()
+		return p.parseRefBody()
+	}
+	if err := p.expectSymbol("{"); err != nil {
+		return err
+	}
+	for {
+		p.skipNewlines()
+		if p.eof() {
+			return p.errorf("unterminated Ref")
+		}
+		if p.peek().IsSymbol("}") {
+			p.next()
+			return nil
+		}
+		if err := p.parseRefBody(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *parser) parseRefBody() error {
+	from, err := p.endpoint()
+	if err != nil {
+		return err
+	}
+	op := p.next()
+	if op.Kind != TokenSymbol || !isRefOperator(op.Value) {
+		return p.errorf("expected relationship operator but got '%s'", op.Value)
+	}
+	to, err := p.endpoint()
+	if err != nil {
+		return err
+	}
+	settings, err := p.parseSettings()
+	if err != nil {
+		return err
+	}
+	p.refs = append(p.refs, &ref{from: from, to: to, op: op.Value, settings: settings})
+	return nil
+}
+
+func (p *parser) parseEnum() error {
+	p.next() // Enum
+	parts, err := p.name()
+	if err != nil {
+		return err
+	}
+	e := &schema.Enum{Name: strings.Join(parts, ".")}
+	if err := p.expectSymbol("{"); err != nil {
+		return err
+	}
+	for {
+		p.skipNewlines()
+		if p.eof() {
+			return p.errorf("unterminated Enum %s", e.Name)
+		}
+		if p.peek().IsSymbol("}") {
+			p.next()
+			p.enums = append(p.enums, e)
+			return nil
+		}
+		e.Values = append(e.Values, p.next().Value)
+		if _, err := p.parseSettings(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *parser) parseTableGroup() error {
+	p.next() // TableGroup
+	parts, err := p.name()
+	if err != nil {
+		return err
+	}
+	g := &tableGroup{name: strings.Join(parts, ".")}
+	settings, err := p.parseSettings()
+	if err != nil {
+		return err
+	}
+	for _, s := range settings {
+		if s.key == "note" && len(s.value) > 0 {
+			g.note = s.value[0].Value
+		}
+	}
+	if err := p.expectSymbol("{"); err != nil {
+		return err
+	}
+	for {
+		p.skipNewlines()
+		tok := p.peek()
+		switch {
+		case p.eof():
+			return p.errorf("unterminated TableGroup %s", g.name)
+		case tok.IsSymbol("}"):
+			p.next()
+			p.groups = append(p.groups, g)
+			return nil
+		case tok.Is("Note") && (p.peekAt(1).IsSymbol(":") || p.peekAt(1).IsSymbol("{")):
+			note, err := p.parseNote()
+			if err != nil {
+				return err
+			}
+			g.note = note
+		default:
+			parts, err := p.name()
+			if err != nil {
+				return err
+			}
+			g.tables = append(g.tables, strings.Join(parts, "."))
+		}
+	}
+}
+
+func isRefOperator(op string) bool {
+	switch op {
+	case ">", "<", "-", "<>":
+		return true
+	}
+	return false
+}
+
+// defaultValue return the default value as SQL expression
+func defaultValue(value []Token) string {
+	t := value[0]
+	switch t.Kind {
+	case TokenString:
+		return fmt.Sprintf("'%s'", strings.ReplaceAll(t.Value, "'", "''"))
+	case TokenExpr:
+		return t.Value
+	default:
+		vs := []string{}
+		for _, v := range value {
+			vs = append(vs, v.Value)
+		}
+		return strings.Join(vs, "")
+	}
+}
@@ -0,0 +1,151 @@
+package dbml
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/tbls/schema"
+)
+
+func TestParse(t *testing.T) {
+	b, err := os.ReadFile("../testdata/dbml/blog.dbml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := Parse(string(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := s.Name, "blog"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := s.Desc, "# Blog\nSchema of the blog service"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := s.Driver.Name, "postgres"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := s.Enums, []*schema.Enum{{Name: "post_status", Values: []string{"draft", "published", "in review"}}}; !cmp.Equal(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+
+	users, err := s.FindTableByName("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := users.Comment, "Users table"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	created, _ := users.FindColumnByName("created")
+	if got, want := created.Type, "timestamp with time zone"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := created.Default.String, "now()"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	id, _ := users.FindColumnByName("id")
+	if id.Nullable || id.ExtraDef != "auto_increment" {
+		t.Errorf("unexpected id column: %#v", id)
+	}
+	if got, want := constraintNames(users), []string{"users_pkey", "users_username_key"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+
+	posts, _ := s.FindTableByName("posts")
+	if got, want := posts.Comment, "Posts table"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	title, _ := posts.FindColumnByName("title")
+	if got, want := title.Default.String, "'Untitled'"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	score, _ := posts.FindColumnByName("score")
+	if got, want := score.Type+" "+score.Default.String, "decimal(10,2) -1.5"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := indexNames(posts), []string{"posts_pkey", "posts_user_id_title_idx", "posts_lower(title)_idx"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := posts.Indexes[1].Def, "CREATE UNIQUE INDEX posts_user_id_title_idx ON posts (user_id, title)"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+
+	comments, _ := s.FindTableByName("comments")
+	if got, want := comments.Constraints[0].Def, "PRIMARY KEY (post_id, user_id)"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+
+	if got, want := len(s.Relations), 3; got != want {
+		t.Fatalf("got %v\nwant %v", got, want)
+	}
+	tests := []struct {
+		table             string
+		parentTable       string
+		def               string
+		cardinality       schema.Cardinality
+		parentCardinality schema.Cardinality
+	}{
+		{"posts", "users", "FOREIGN KEY (user_id) REFERENCES users (id)", schema.ZeroOrMore, schema.ExactlyOne},
+		{"comments", "posts", "FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE", schema.ZeroOrMore, schema.ExactlyOne},
+		{"comments", "users", "FOREIGN KEY (user_id) REFERENCES users (id)", schema.ZeroOrMore, schema.ExactlyOne},
+	}
+	for i, tt := range tests {
+		r := s.Relations[i]
+		if r.Table.Name != tt.table || r.ParentTable.Name != tt.parentTable || r.Def != tt.def || r.Cardinality != tt.cardinality || r.ParentCardinality != tt.parentCardinality {
+			t.Errorf("unexpected relation %d: %s -> %s %s %s %s", i, r.Table.Name, r.ParentTable.Name, r.Def, r.Cardinality, r.ParentCardinality)
+		}
+	}
+	if got, want := len(id.ChildRelations), 2; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+
+	if got, want := len(s.Viewpoints), 1; got != want {
+		t.Fatalf("got %v\nwant %v", got, want)
+	}
+	v := s.Viewpoints[0]
+	if got, want := v.Name+" "+v.Desc, "content Contents"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := v.Tables, []string{"posts", "comments"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Table users {\n  id int\n", "line 2: unterminated Table users"},
+		{"Table users {\n  id int\n}\nRef: users.id > posts.id", "line 4: not found table 'posts'"},
+		{"Table users {\n  id int [pk\n}", "line 3: unterminated settings"},
+		{"Ref: users.id >> posts.id", "line 1: expected name but got '>'"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.in)
+		if err == nil {
+			t.Errorf("want error: %s", tt.in)
+			continue
+		}
+		if got := err.Error(); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func constraintNames(t *schema.Table) []string {
+	names := []string{}
+	for _, c := range t.Constraints {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func indexNames(t *schema.Table) []string {
+	names := []string{}
+	for _, i := range t.Indexes {
+		names = append(names, i.Name)
+	}
+	return names
+}
@@ -0,0 +1,956 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/schema"
+	"github.com/samber/lo"
+)
+
+const (
+	DialectPostgres = "postgres"
+	DialectMySQL    = "mysql"
+	DialectSQLite   = "sqlite"
+)
+
+const defaultPostgresSchema = "public"
+
+const (
+	typePK     = "PRIMARY KEY"
+	typeUnique = "UNIQUE"
+)
+
+// columnConstraintKeywords are the keywords that terminate the data type of column definition
+var columnConstraintKeywords = []string{
+	"NOT", "NULL", "DEFAULT", "PRIMARY", "UNIQUE", "REFERENCES", "CHECK", "CONSTRAINT",
+	"AUTO_INCREMENT", "AUTOINCREMENT", "COMMENT", "GENERATED", "COLLATE", "ON", "CHARSET", "AS", "IDENTITY",
+}
+
+// Builder builds schema from DDL statements
+type Builder struct {
+	name      string
+	dialect   string
+	tables    []*schema.Table
+	enums     []*schema.Enum
+	functions []*schema.Function
+	seq       map[string]int
+}
+
+// NewBuilder return new Builder
+func NewBuilder(name, dialect string) (*Builder, error) {
+	switch dialect {
+	case DialectPostgres, DialectMySQL, DialectSQLite:
+	default:
+		return nil, fmt.Errorf("unsupported dialect '%s'. supported dialects are postgres, mysql and sqlite", dialect)
+	}
+	return &Builder{
+		name:    name,
+		dialect: dialect,
+		seq:     map[string]int{},
+	}, nil
+}
+
+// Parse parses DDL statements and applies them to the schema.
+// Statements other than CREATE, ALTER TABLE, DROP and COMMENT ON are ignored.
+func (b *Builder) Parse(src string) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	for _, tokens := range SplitStatements(Tokenize(src, b.dialect)) {
+		st := &stmt{src: src, tokens: tokens, fold: b.dialect == DialectPostgres}
+		switch {
+		case st.peek().Is("CREATE"):
+			err = b.parseCreate(st)
+		case st.peek().Is("ALTER") && st.peekN(1).Is("TABLE"):
+			err = b.parseAlterTable(st)
+		case st.peek().Is("DROP"):
+			err = b.parseDrop(st)
+		case st.peek().Is("COMMENT") && st.peekN(1).Is("ON"):
+			err = b.parseComment(st)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse '%s': %w", st.raw(0, len(st.tokens)), err)
+		}
+	}
+	return nil
+}
+
+// Build return the schema built from parsed statements
+func (b *Builder) Build() (_ *schema.Schema, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	s := &schema.Schema{
+		Name:      b.name,
+		Tables:    b.tables,
+		Enums:     b.enums,
+		Functions: b.functions,
+		Driver: &schema.Driver{
+			Name: b.dialect,
+			Meta: &schema.DriverMeta{},
+		},
+	}
+	if b.dialect == DialectPostgres {
+		s.Driver.Meta.CurrentSchema = defaultPostgresSchema
+		s.Driver.Meta.SearchPaths = []string{defaultPostgresSchema}
+	}
+	relations := []*schema.Relation{}
+	for _, t := range s.Tables {
+		for _, c := range t.Constraints {
+			if c.Type != schema.TypeFK || c.ReferencedTable == nil {
+				continue
+			}
+			pt, err := s.FindTableByName(*c.ReferencedTable)
+			if err != nil {
+				// the parent table is not defined in DDL
+				continue
+			}
+			if len(c.ReferencedColumns) == 0 {
+				c.ReferencedColumns = primaryKeyColumns(pt)
+			}
+			r := &schema.Relation{
+				Table:       t,
+				ParentTable: pt,
+				Def:         c.Def,
+			}
+			for _, cn := range c.Columns {
+				col, err := t.FindColumnByName(cn)
+				if err != nil {
+					return nil, err
+				}
+				r.Columns = append(r.Columns, col)
+				col.ParentRelations = append(col.ParentRelations, r)
+			}
+			for _, cn := range c.ReferencedColumns {
+				col, err := pt.FindColumnByName(cn)
+				if err != nil {
+					return nil, err
+				}
+				r.ParentColumns = append(r.ParentColumns, col)
+				col.ChildRelations = append(col.ChildRelations, r)
+			}
+			relations = append(relations, r)
+		}
+		if strings.Contains(t.Type, "VIEW") || t.Type == "view" {
+			for _, rt := range ParseReferencedTables(t.Def) {
+				tt, err := s.FindTableByName(b.normalizeName(rt))
+				if err != nil {
+					continue
+				}
+				t.ReferencedTables = append(t.ReferencedTables, tt)
+			}
+		}
+	}
+	s.Relations = relations
+	return s, nil
+}
+
+func (b *Builder) parseCreate(st *stmt) error {
+	st.next() // CREATE
+	materialized := false
+	for !st.eof() {
+		t := st.next()
+		switch {
+		case t.Is("TABLE"):
+			return b.parseCreateTable(st)
+		case t.Is("VIEW"):
+			return b.parseCreateView(st, materialized)
+		case t.Is("INDEX"):
+			return b.parseCreateIndex(st)
+		case t.Is("TYPE"):
+			return b.parseCreateType(st)
+		case t.Is("FUNCTION", "PROCEDURE"):
+			return b.parseCreateFunction(st, strings.ToUpper(t.Value))
+		case t.Is("TRIGGER"):
+			return b.parseCreateTrigger(st)
+		case t.Is("MATERIALIZED"):
+			materialized = true
+		case t.IsSymbol("="):
+			// e.g. ALGORITHM=UNDEFINED, DEFINER=`root`@`localhost`
+			st.next()
+			if st.peek().IsSymbol("@") {
+				st.next()
+				st.next()
+			}
+		case t.Kind != TokenWord:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (b *Builder) parseCreateTable(st *stmt) error {
+	st.acceptWords("IF", "NOT", "EXISTS")
+	name, ok := st.name()
+	if !ok {
+		return errors.New("invalid table name")
+	}
+	if !st.peek().IsSymbol("(") {
+		// CREATE TABLE ... AS SELECT, CREATE TABLE ... LIKE, etc.
+		return nil
+	}
+	t := &schema.Table{
+		Name: b.normalizeName(name),
+		Type: b.tableType(false, false),
+		Def:  st.raw(0, len(st.tokens)),
+	}
+	if _, err := b.findTable(t.Name); err == nil {
+		b.dropTable(t.Name)
+	}
+	b.tables = append(b.tables, t)
+	start := st.pos
+	end := st.closeParen(start)
+	for _, el := range st.splitComma(start, end) {
+		if err := b.parseTableElement(t, el); err != nil {
+			return err
+		}
+	}
+	st.pos = end + 1
+	// table options
+	for !st.eof() {
+		if st.peek().Is("COMMENT") {
+			st.next()
+			if st.peek().IsSymbol("=") {
+				st.next()
+			}
+			if st.peek().Kind == TokenString {
+				t.Comment = st.peek().Value
+			}
+		}
+		st.next()
+	}
+	return nil
+}
+
+func (b *Builder) parseTableElement(t *schema.Table, el *stmt) error {
+	switch {
+	case el.peek().Is("CONSTRAINT", "PRIMARY", "FOREIGN", "CHECK", "EXCLUDE", "UNIQUE"):
+		return b.parseTableConstraint(t, el)
+	case el.peek().Is("KEY", "INDEX", "FULLTEXT", "SPATIAL") && b.dialect == DialectMySQL:
+		// MySQL inline index
+		el.acceptWords("FULLTEXT")
+		el.acceptWords("SPATIAL")
+		el.next() // KEY or INDEX
+		name, _ := el.name()
+		cols := el.columnList()
+		t.Indexes = append(t.Indexes, &schema.Index{
+			Name:    name,
+			Def:     el.raw(0, len(el.tokens)),
+			Table:   &t.Name,
+			Columns: cols,
+		})
+		return nil
+	case el.peek().Is("LIKE", "PERIOD"):
+		return nil
+	}
+	c, err := b.parseColumn(t, el)
+	if err != nil {
+		return err
+	}
+	t.Columns = append(t.Columns, c)
+	return nil
+}
+
+// parseColumn parses column definition including column constraints
+func (b *Builder) parseColumn(t *schema.Table, el *stmt) (*schema.Column, error) {
+	name, ok := el.ident()
+	if !ok {
+		return nil, fmt.Errorf("invalid column definition: %s", el.raw(0, len(el.tokens)))
+	}
+	c := &schema.Column{
+		Name:     name,
+		Nullable: true,
+	}
+	c.Type = el.dataType()
+	constraintName := ""
+	for !el.eof() {
+		tok := el.peek()
+		switch {
+		case tok.Is("CONSTRAINT"):
+			el.next()
+			constraintName, _ = el.ident()
+			continue
+		case tok.Is("NOT") && el.peekN(1).Is("NULL"):
+			c.Nullable = false
+			el.next()
+		case tok.Is("NULL"):
+		case tok.Is("DEFAULT"):
+			el.next()
+			start := el.pos
+			el.skipUntil(columnConstraintKeywords...)
+			c.Default.String = el.raw(start, el.pos)
+			c.Default.Valid = true
+			continue
+		case tok.Is("PRIMARY") && el.peekN(1).Is("KEY"):
+			el.next()
+			c.Nullable = false
+			b.addConstraint(t, constraintName, typePK, []string{c.Name}, "")
+			constraintName = ""
+		case tok.Is("UNIQUE"):
+			el.next()
+			el.acceptWords("KEY")
+			b.addConstraint(t, constraintName, typeUnique, []string{c.Name}, "")
+			constraintName = ""
+			continue
+		case tok.Is("REFERENCES"):
+			start := el.pos
+			el.next()
+			pt, _ := el.name()
+			pcols := el.columnList()
+		refs:
+			for !el.eof() {
+				switch {
+				case el.peek().Is("ON") && el.peekN(1).Is("DELETE", "UPDATE"):
+					// ON DELETE / ON UPDATE action
+					el.next()
+					el.next()
+					if el.peek().Is("SET", "NO") {
+						el.next()
+					}
+					el.next()
+					continue
+				case el.peek().Is("MATCH", "DEFERRABLE", "INITIALLY", "DEFERRED", "IMMEDIATE", "FULL", "PARTIAL", "SIMPLE"):
+					el.next()
+					continue
+				}
+				break refs
+			}
+			def := fmt.Sprintf("FOREIGN KEY (%s) %s", c.Name, el.raw(start, el.pos))
+			cs := b.addConstraint(t, constraintName, schema.TypeFK, []string{c.Name}, def)
+			cs.ReferencedTable = lo.ToPtr(b.normalizeName(pt))
+			cs.ReferencedColumns = pcols
+			constraintName = ""
+			continue
+		case tok.Is("CHECK"):
+			start := el.pos
+			el.next()
+			if el.peek().IsSymbol("(") {
+				el.pos = el.closeParen(el.pos)
+			}
+			b.addConstraint(t, constraintName, "CHECK", []string{c.Name}, el.raw(start, el.pos+1))
+			constraintName = ""
+		case tok.Is("AUTO_INCREMENT"):
+			c.ExtraDef = strings.TrimSpace(c.ExtraDef + " auto_increment")
+		case tok.Is("COMMENT") && el.peekN(1).Kind == TokenString:
+			el.next()
+			c.Comment = el.peek().Value
+		case tok.Is("GENERATED") || (tok.Is("AS") && el.peekN(1).IsSymbol("(")):
+			start := el.pos
+			el.next()
+			for !el.eof() && !el.peek().IsSymbol("(") && !el.peek().Is("IDENTITY") {
+				el.next()
+			}
+			if el.peek().IsSymbol("(") {
+				el.pos = el.closeParen(el.pos)
+			}
+			el.next()
+			el.acceptWords("STORED")
+			el.acceptWords("VIRTUAL")
+			c.ExtraDef = strings.TrimSpace(c.ExtraDef + " " + el.raw(start, el.pos))
+			continue
+		case tok.Is("ON") && el.peekN(1).Is("UPDATE"):
+			start := el.pos
+			el.next()
+			el.next()
+			el.skipUntil(columnConstraintKeywords...)
+			c.ExtraDef = strings.TrimSpace(c.ExtraDef + " " + strings.ToLower(el.raw(start, el.pos)))
+			continue
+		}
+		el.next()
+	}
+	return c, nil
+}
+
+func (b *Builder) parseTableConstraint(t *schema.Table, el *stmt) error {
+	name := ""
+	if el.peek().Is("CONSTRAINT") {
+		el.next()
+		if !el.peek().Is("PRIMARY", "UNIQUE", "FOREIGN", "CHECK", "EXCLUDE") {
+			name, _ = el.ident()
+		}
+	}
+	start := el.pos
+	def := el.raw(start, len(el.tokens))
+	switch {
+	case el.peek().Is("PRIMARY"):
+		el.acceptWords("PRIMARY", "KEY")
+		b.addConstraint(t, name, typePK, el.columnList(), def)
+	case el.peek().Is("UNIQUE"):
+		el.next()
+		if el.peek().Is("KEY", "INDEX") {
+			el.next()
+		}
+		if el.peek().IsIdent() {
+			// MySQL: UNIQUE KEY name (columns)
+			n, _ := el.ident()
+			if name == "" {
+				name = n
+			}
+		}
+		b.addConstraint(t, name, typeUnique, el.columnList(), def)
+	case el.peek().Is("FOREIGN"):
+		el.acceptWords("FOREIGN", "KEY")
+		if el.peek().IsIdent() {
+			// MySQL: FOREIGN KEY name (columns)
+			n, _ := el.ident()
+			if name == "" {
+				name = n
+			}
+		}
+		cols := el.columnList()
+		if !el.peek().Is("REFERENCES") {
+			return fmt.Errorf("invalid foreign key: %s", def)
+		}
+		el.next()
+		pt, _ := el.name()
+		cs := b.addConstraint(t, name, schema.TypeFK, cols, def)
+		cs.ReferencedTable = lo.ToPtr(b.normalizeName(pt))
+		cs.ReferencedColumns = el.columnList()
+	case el.peek().Is("CHECK"):
+		b.addConstraint(t, name, "CHECK", nil, def)
+	case el.peek().Is("EXCLUDE"):
+		b.addConstraint(t, name, "EXCLUDE", nil, def)
+	}
+	return nil
+}
+
+// addConstraint adds the constraint to the table. Indexes are also added for PRIMARY KEY and UNIQUE constraints.
+func (b *Builder) addConstraint(t *schema.Table, name, typ string, cols []string, def string) *schema.Constraint {
+	if def == "" {
+		def = fmt.Sprintf("%s (%s)", typ, strings.Join(cols, ", "))
+	}
+	if name == "" {
+		name = b.constraintName(t, typ, cols)
+	}
+	if typ == typePK {
+		for _, cn := range cols {
+			if c, err := t.FindColumnByName(cn); err == nil {
+				c.Nullable = false
+			}
+		}
+	}
+	c := &schema.Constraint{
+		Name:    name,
+		Type:    typ,
+		Def:     def,
+		Table:   &t.Name,
+		Columns: cols,
+	}
+	t.Constraints = append(t.Constraints, c)
+	if typ == typePK || typ == typeUnique {
+		t.Indexes = append(t.Indexes, &schema.Index{
+			Name:    name,
+			Def:     def,
+			Table:   &t.Name,
+			Columns: cols,
+		})
+	}
+	return c
+}
+
+// constraintName return the default name of the constraint
+func (b *Builder) constraintName(t *schema.Table, typ string, cols []string) string {
+	tn := t.Name
+	if i := strings.LastIndex(tn, "."); i >= 0 {
+		tn = tn[i+1:]
+	}
+	if b.dialect == DialectMySQL {
+		switch typ {
+		case typePK:
+			return "PRIMARY"
+		case typeUnique:
+			if len(cols) > 0 {
+				return cols[0]
+			}
+		case schema.TypeFK:
+			b.seq[t.Name+"_ibfk"]++
+			return fmt.Sprintf("%s_ibfk_%d", tn, b.seq[t.Name+"_ibfk"])
+		}
+		b.seq[t.Name+"_chk"]++
+		return fmt.Sprintf("%s_chk_%d", tn, b.seq[t.Name+"_chk"])
+	}
+	switch typ {
+	case typePK:
+		return fmt.Sprintf("%s_pkey", tn)
+	case typeUnique:
+		return fmt.Sprintf("%s_%s_key", tn, strings.Join(cols, "_"))
+	case schema.TypeFK:
+		return fmt.Sprintf("%s_%s_fkey", tn, strings.Join(cols, "_"))
+	}
+	if len(cols) > 0 {
+		return fmt.Sprintf("%s_%s_%s", tn, strings.Join(cols, "_"), strings.ToLower(typ))
+	}
+	return fmt.Sprintf("%s_%s", tn, strings.ToLower(typ))
+}
+
+func (b *Builder) parseCreateView(st *stmt, materialized bool) error {
+	st.acceptWords("IF", "NOT", "EXISTS")
+	name, ok := st.name()
+	if !ok {
+		return errors.New("invalid view name")
+	}
+	t := &schema.Table{
+		Name: b.normalizeName(name),
+		Type: b.tableType(true, materialized),
+		Def:  st.raw(0, len(st.tokens)),
+	}
+	if st.peek().IsSymbol("(") {
+		for _, cn := range st.columnList() {
+			t.Columns = append(t.Columns, &schema.Column{Name: cn, Nullable: true})
+		}
+	}
+	if len(t.Columns) == 0 {
+		for _, cn := range st.selectColumns() {
+			t.Columns = append(t.Columns, &schema.Column{Name: cn, Nullable: true})
+		}
+	}
+	if _, err := b.findTable(t.Name); err == nil {
+		b.dropTable(t.Name)
+	}
+	b.tables = append(b.tables, t)
+	return nil
+}
+
+func (b *Builder) parseCreateIndex(st *stmt) error {
+	st.acceptWords("CONCURRENTLY")
+	st.acceptWords("IF", "NOT", "EXISTS")
+	name := ""
+	if !st.peek().Is("ON") {
+		name, _ = st.ident()
+	}
+	if !st.peek().Is("ON") {
+		// e.g. CREATE INDEX name USING BTREE ON ...
+		st.skipUntil("ON")
+	}
+	st.next() // ON
+	st.acceptWords("ONLY")
+	tn, ok := st.name()
+	if !ok {
+		return errors.New("invalid table name")
+	}
+	t, err := b.findTable(b.normalizeName(tn))
+	if err != nil {
+		return err
+	}
+	if st.peek().Is("USING") {
+		st.next()
+		st.next()
+	}
+	cols := st.columnList()
+	if name == "" {
+		name = fmt.Sprintf("%s_%s_idx", t.Name, strings.Join(cols, "_"))
+	}
+	t.Indexes = append(t.Indexes, &schema.Index{
+		Name:    name,
+		Def:     st.raw(0, len(st.tokens)),
+		Table:   &t.Name,
+		Columns: cols,
+	})
+	return nil
+}
+
+func (b *Builder) parseCreateType(st *stmt) error {
+	name, ok := st.name()
+	if !ok || !st.acceptWords("AS", "ENUM") {
+		return nil
+	}
+	e := &schema.Enum{Name: b.normalizeName(name)}
+	end := st.closeParen(st.pos)
+	for i := st.pos + 1; i < end; i++ {
+		if st.tokens[i].Kind == TokenString {
+			e.Values = append(e.Values, st.tokens[i].Value)
+		}
+	}
+	b.enums = append(b.enums, e)
+	return nil
+}
+
+func (b *Builder) parseCreateFunction(st *stmt, typ string) error {
+	name, ok := st.name()
+	if !ok || !st.peek().IsSymbol("(") {
+		return nil
+	}
+	end := st.closeParen(st.pos)
+	f := &schema.Function{
+		Name:      b.normalizeName(name),
+		Type:      typ,
+		Arguments: st.raw(st.pos+1, end),
+	}
+	st.pos = end + 1
+	if st.peek().Is("RETURNS") {
+		st.next()
+		start := st.pos
+		st.skipUntil("LANGUAGE", "AS", "IMMUTABLE", "STABLE", "VOLATILE", "STRICT", "SECURITY", "BEGIN", "DETERMINISTIC", "NO", "READS", "MODIFIES", "CONTAINS", "RETURN", "COMMENT", "SET", "PARALLEL", "COST", "CALLED", "LEAKPROOF", "WINDOW")
+		f.ReturnType = st.raw(start, st.pos)
+	}
+	b.functions = lo.Reject(b.functions, func(ff *schema.Function, _ int) bool {
+		return ff.Name == f.Name && ff.Arguments == f.Arguments
+	})
+	b.functions = append(b.functions, f)
+	return nil
+}
+
+func (b *Builder) parseCreateTrigger(st *stmt) error {
+	st.acceptWords("IF", "NOT", "EXISTS")
+	name, ok := st.name()
+	if !ok {
+		return errors.New("invalid trigger name")
+	}
+	st.skipUntil("ON")
+	st.next()
+	tn, ok := st.name()
+	if !ok {
+		return errors.New("invalid table name")
+	}
+	t, err := b.findTable(b.normalizeName(tn))
+	if err != nil {
+		// e.g. event trigger
+		return nil
+	}
+	t.Triggers = append(t.Triggers, &schema.Trigger{
+		Name: name,
+		Def:  st.raw(0, len(st.tokens)),
+	})
+	return nil
+}
+
+func (b *Builder) parseAlterTable(st *stmt) error {
+	st.next() // ALTER
+	st.next() // TABLE
+	st.acceptWords("IF", "EXISTS")
+	st.acceptWords("ONLY")
+	tn, ok := st.name()
+	if !ok {
+		return errors.New("invalid table name")
+	}
+	t, err := b.findTable(b.normalizeName(tn))
+	if err != nil {
+		return err
+	}
+	for _, action := range st.splitComma(st.pos-1, len(st.tokens)) {
+		if err := b.parseAlterAction(t, action); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *Builder) parseAlterAction(t *schema.Table, a *stmt) error {
+	switch {
+	case a.peek().Is("ADD"):
+		a.next()
+		switch {
+		case a.peek().Is("CONSTRAINT", "PRIMARY", "FOREIGN", "CHECK", "EXCLUDE", "UNIQUE"):
+			return b.parseTableElement(t, a.rest())
+		case a.peek().Is("KEY", "INDEX", "FULLTEXT", "SPATIAL"):
+			return b.parseTableElement(t, a.rest())
+		}
+		a.acceptWords("COLUMN")
+		a.acceptWords("IF", "NOT", "EXISTS")
+		c, err := b.parseColumn(t, a.rest())
+		if err != nil {
+			return err
+		}
+		t.Columns = append(t.Columns, c)
+	case a.peek().Is("DROP"):
+		a.next()
+		switch {
+		case a.peek().Is("CONSTRAINT", "FOREIGN", "CHECK"):
+			a.next()
+			a.acceptWords("KEY")
+			a.acceptWords("IF", "EXISTS")
+			name, _ := a.ident()
+			b.dropConstraint(t, name)
+		case a.peek().Is("PRIMARY"):
+			if pk, ok := lo.Find(t.Constraints, func(c *schema.Constraint) bool { return c.Type == typePK }); ok {
+				b.dropConstraint(t, pk.Name)
+			}
+		case a.peek().Is("INDEX", "KEY"):
+			a.next()
+			name, _ := a.ident()
+			b.dropConstraint(t, name)
+			t.Indexes = lo.Reject(t.Indexes, func(i *schema.Index, _ int) bool { return i.Name == name })
+		default:
+			a.acceptWords("COLUMN")
+			a.acceptWords("IF", "EXISTS")
+			name, _ := a.ident()
+			b.dropColumn(t, name)
+		}
+	case a.peek().Is("ALTER"):
+		a.next()
+		a.acceptWords("COLUMN")
+		name, _ := a.ident()
+		c, err := t.FindColumnByName(name)
+		if err != nil {
+			return err
+		}
+		switch {
+		case a.acceptWords("SET", "NOT", "NULL"):
+			c.Nullable = false
+		case a.acceptWords("DROP", "NOT", "NULL"):
+			c.Nullable = true
+		case a.acceptWords("SET", "DEFAULT"):
+			c.Default.String = a.raw(a.pos, len(a.tokens))
+			c.Default.Valid = true
+		case a.acceptWords("DROP", "DEFAULT"):
+			c.Default.String = ""
+			c.Default.Valid = false
+		case a.peek().Is("TYPE") || a.acceptWords("SET", "DATA"):
+			a.acceptWords("TYPE")
+			c.Type = a.dataType()
+		}
+	case a.peek().Is("MODIFY", "CHANGE"):
+		change := a.peek().Is("CHANGE")
+		a.next()
+		a.acceptWords("COLUMN")
+		old := ""
+		if change {
+			old, _ = a.ident()
+		}
+		c, err := b.parseColumn(t, a.rest())
+		if err != nil {
+			return err
+		}
+		if !change {
+			old = c.Name
+		}
+		for i, oc := range t.Columns {
+			if oc.Name == old {
+				t.Columns[i] = c
+				b.renameColumn(t, old, c.Name)
+				return nil
+			}
+		}
+		return fmt.Errorf("not found column '%s'", old)
+	case a.peek().Is("RENAME"):
+		a.next()
+		switch {
+		case a.peek().Is("TO", "AS"):
+			a.next()
+			name, _ := a.name()
+			b.renameTable(t, b.normalizeName(name))
+		case a.peek().Is("COLUMN") || a.peekN(1).Is("TO"):
+			a.acceptWords("COLUMN")
+			old, _ := a.ident()
+			a.acceptWords("TO")
+			name, _ := a.ident()
+			c, err := t.FindColumnByName(old)
+			if err != nil {
+				return err
+			}
+			c.Name = name
+			b.renameColumn(t, old, name)
+		}
+	}
+	return nil
+}
+
+func (b *Builder) parseDrop(st *stmt) error {
+	st.next() // DROP
+	st.acceptWords("MATERIALIZED")
+	switch {
+	case st.peek().Is("TABLE", "VIEW"):
+		st.next()
+		st.acceptWords("IF", "EXISTS")
+		for _, el := range st.splitComma(st.pos-1, len(st.tokens)) {
+			name, _ := el.name()
+			b.dropTable(b.normalizeName(name))
+		}
+	case st.peek().Is("INDEX"):
+		st.next()
+		st.acceptWords("CONCURRENTLY")
+		st.acceptWords("IF", "EXISTS")
+		name, _ := st.name()
+		if i := strings.LastIndex(name, "."); i >= 0 && b.dialect == DialectPostgres {
+			name = name[i+1:]
+		}
+		for _, t := range b.tables {
+			t.Indexes = lo.Reject(t.Indexes, func(i *schema.Index, _ int) bool { return i.Name == name })
+		}
+	case st.peek().Is("TYPE"):
+		st.next()
+		st.acceptWords("IF", "EXISTS")
+		name, _ := st.name()
+		name = b.normalizeName(name)
+		b.enums = lo.Reject(b.enums, func(e *schema.Enum, _ int) bool { return e.Name == name })
+	case st.peek().Is("FUNCTION", "PROCEDURE"):
+		st.next()
+		st.acceptWords("IF", "EXISTS")
+		name, _ := st.name()
+		name = b.normalizeName(name)
+		b.functions = lo.Reject(b.functions, func(f *schema.Function, _ int) bool { return f.Name == name })
+	case st.peek().Is("TRIGGER"):
+		st.next()
+		st.acceptWords("IF", "EXISTS")
+		name, _ := st.name()
+		for _, t := range b.tables {
+			t.Triggers = lo.Reject(t.Triggers, func(g *schema.Trigger, _ int) bool { return g.Name == name })
+		}
+	}
+	return nil
+}
+
+func (b *Builder) parseComment(st *stmt) error {
+	st.next() // COMMENT
+	st.next() // ON
+	st.acceptWords("MATERIALIZED")
+	kind := strings.ToUpper(st.next().Value)
+	name, ok := st.name()
+	if !ok {
+		return errors.New("invalid comment target")
+	}
+	on := ""
+	if st.peek().Is("ON") {
+		st.next()
+		on, _ = st.name()
+	}
+	if !st.acceptWords("IS") {
+		return errors.New("invalid comment")
+	}
+	comment := ""
+	if st.peek().Kind == TokenString {
+		comment = st.peek().Value
+	}
+	switch kind {
+	case "TABLE", "VIEW":
+		t, err := b.findTable(b.normalizeName(name))
+		if err != nil {
+			return err
+		}
+		t.Comment = comment
+	case "COLUMN":
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			return fmt.Errorf("invalid column name '%s'", name)
+		}
+		t, err := b.findTable(b.normalizeName(name[:i]))
+		if err != nil {
+			return err
+		}
+		c, err := t.FindColumnByName(name[i+1:])
+		if err != nil {
+			return err
+		}
+		c.Comment = comment
+	case "INDEX":
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+		for _, t := range b.tables {
+			for _, idx := range t.Indexes {
+				if idx.Name == name {
+					idx.Comment = comment
+				}
+			}
+		}
+	case "CONSTRAINT", "TRIGGER":
+		t, err := b.findTable(b.normalizeName(on))
+		if err != nil {
+			return err
+		}
+		for _, c := range t.Constraints {
+			if kind == "CONSTRAINT" && c.Name == name {
+				c.Comment = comment
+			}
+		}
+		for _, g := range t.Triggers {
+			if kind == "TRIGGER" && g.Name == name {
+				g.Comment = comment
+			}
+		}
+	}
+	return nil
+}
+
+func (b *Builder) findTable(name string) (*schema.Table, error) {
+	t, ok := lo.Find(b.tables, func(t *schema.Table) bool { return t.Name == name })
+	if !ok {
+		return nil, fmt.Errorf("not found table '%s'", name)
+	}
+	return t, nil
+}
+
+func (b *Builder) dropTable(name string) {
+	b.tables = lo.Reject(b.tables, func(t *schema.Table, _ int) bool { return t.Name == name })
+}
+
+func (b *Builder) renameTable(t *schema.Table, name string) {
+	old := t.Name
+	t.Name = name
+	for _, tt := range b.tables {
+		for _, c := range tt.Constraints {
+			if c.ReferencedTable != nil && *c.ReferencedTable == old {
+				c.ReferencedTable = lo.ToPtr(name)
+			}
+		}
+	}
+}
+
+func (b *Builder) dropColumn(t *schema.Table, name string) {
+	t.Columns = lo.Reject(t.Columns, func(c *schema.Column, _ int) bool { return c.Name == name })
+	t.Indexes = lo.Reject(t.Indexes, func(i *schema.Index, _ int) bool { return lo.Contains(i.Columns, name) })
+	t.Constraints = lo.Reject(t.Constraints, func(c *schema.Constraint, _ int) bool { return lo.Contains(c.Columns, name) })
+}
+
+func (b *Builder) renameColumn(t *schema.Table, old, name string) {
+	if old == name {
+		return
+	}
+	replace := func(cols []string) {
+		for i, c := range cols {
+			if c == old {
+				cols[i] = name
+			}
+		}
+	}
+	for _, i := range t.Indexes {
+		replace(i.Columns)
+	}
+	for _, c := range t.Constraints {
+		replace(c.Columns)
+	}
+	for _, tt := range b.tables {
+		for _, c := range tt.Constraints {
+			if c.ReferencedTable != nil && *c.ReferencedTable == t.Name {
+				replace(c.ReferencedColumns)
+			}
+		}
+	}
+}
+
+func (b *Builder) dropConstraint(t *schema.Table, name string) {
+	t.Constraints = lo.Reject(t.Constraints, func(c *schema.Constraint, _ int) bool { return c.Name == name })
+	t.Indexes = lo.Reject(t.Indexes, func(i *schema.Index, _ int) bool { return i.Name == name && i.Def != "" && !strings.HasPrefix(strings.ToUpper(i.Def), "CREATE") })
+}
+
+// normalizeName qualifies the table name with the default schema in PostgreSQL
+func (b *Builder) normalizeName(name string) string {
+	if b.dialect == DialectPostgres && name != "" && !strings.Contains(name, ".") {
+		return fmt.Sprintf("%s.%s", defaultPostgresSchema, name)
+	}
+	return name
+}
+
+func (b *Builder) tableType(view, materialized bool) string {
+	switch {
+	case b.dialect == DialectSQLite && view:
+		return "view"
+	case b.dialect == DialectSQLite:
+		return "table"
+	case materialized:
+		return "MATERIALIZED VIEW"
+	case view:
+		return "VIEW"
+	}
+	return "BASE TABLE"
+}
+
+func primaryKeyColumns(t *schema.Table) []string {
+	pk, ok := lo.Find(t.Constraints, func(c *schema.Constraint) bool { return c.Type == typePK })
+	if !ok {
+		return nil
+	}
+	return pk.Columns
+}
@@ -0,0 +1,306 @@
+package ddl
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/tbls/schema"
+)
+
+func TestBuilderPostgres(t *testing.T) {
+	src := `
+CREATE TYPE post_types AS ENUM ('public', 'private', 'draft');
+
+CREATE TABLE users (
+  id serial PRIMARY KEY,
+  username varchar (50) UNIQUE NOT NULL CHECK(char_length(username) > 4),
+  email varchar (355) UNIQUE NOT NULL,
+  created timestamp with time zone NOT NULL DEFAULT now(),
+  updated timestamp
+);
+COMMENT ON TABLE users IS 'Users table';
+COMMENT ON COLUMN users.email IS 'ex. user@example.com';
+
+CREATE TABLE posts (
+  id bigserial NOT NULL,
+  user_id int NOT NULL,
+  title varchar (255) NOT NULL DEFAULT 'Untitled',
+  post_type post_types NOT NULL,
+  body text,
+  meta jsonb NOT NULL DEFAULT ('{"a": 1}'::jsonb #- '{a}'),
+  CONSTRAINT posts_id_pk PRIMARY KEY(id),
+  CONSTRAINT posts_user_id_fk FOREIGN KEY(user_id) REFERENCES users(id) ON UPDATE NO ACTION ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX posts_user_id_title_idx ON posts USING btree (user_id, title);
+
+CREATE TABLE comments (
+  id bigserial NOT NULL,
+  post_id bigint NOT NULL REFERENCES posts ON DELETE SET NULL,
+  user_id int NOT NULL,
+  comment text NOT NULL
+);
+ALTER TABLE ONLY comments ADD CONSTRAINT comments_id_pk PRIMARY KEY (id);
+ALTER TABLE comments ADD CONSTRAINT comments_user_id_fk FOREIGN KEY (user_id) REFERENCES users(id);
+ALTER TABLE comments ADD COLUMN created timestamp NOT NULL, DROP COLUMN comment;
+
+CREATE VIEW post_comments AS (
+  SELECT c.id, p.title, u.username AS post_user, c.created
+  FROM posts p
+  LEFT JOIN comments c ON p.id = c.post_id
+  LEFT JOIN users u ON u.id = p.user_id
+);
+
+CREATE FUNCTION update_updated() RETURNS trigger AS $$
+BEGIN
+  NEW.updated = now();
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER update_users_updated BEFORE UPDATE ON users FOR EACH ROW EXECUTE PROCEDURE update_updated();
+`
+	b, err := NewBuilder("testdb", DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Parse(src); err != nil {
+		t.Fatal(err)
+	}
+	s, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := tableNames(s), []string{"public.users", "public.posts", "public.comments", "public.post_comments"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+
+	users, err := s.FindTableByName("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := users.Comment, "Users table"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := columnTypes(users), []string{"id serial", "username varchar(50)", "email varchar(355)", "created timestamp with time zone", "updated timestamp"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	email, _ := users.FindColumnByName("email")
+	if got, want := email.Comment, "ex. user@example.com"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	created, _ := users.FindColumnByName("created")
+	if got, want := created.Default.String, "now()"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := constraintNames(users), []string{"users_pkey", "users_username_key", "users_username_check", "users_email_key"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := len(users.Triggers), 1; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+
+	posts, _ := s.FindTableByName("posts")
+	if got, want := indexNames(posts), []string{"posts_id_pk", "posts_user_id_title_idx"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	title, _ := posts.FindColumnByName("title")
+	if got, want := title.Default.String, "'Untitled'"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	// `#` is an operator in PostgreSQL, not a comment
+	meta, _ := posts.FindColumnByName("meta")
+	if got, want := meta.Default.String, `('{"a": 1}'::jsonb #- '{a}')`; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := meta.Nullable, false; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+
+	comments, _ := s.FindTableByName("comments")
+	if got, want := columnNames(comments), []string{"id", "post_id", "user_id", "created"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := constraintNames(comments), []string{"comments_post_id_fkey", "comments_id_pk", "comments_user_id_fk"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+
+	if got, want := len(s.Relations), 3; got != want {
+		t.Fatalf("got %v\nwant %v", got, want)
+	}
+	r := s.Relations[1]
+	if got, want := r.Def, "FOREIGN KEY (post_id) REFERENCES posts ON DELETE SET NULL"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := r.ParentColumns[0].Name, "id"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+
+	view, _ := s.FindTableByName("post_comments")
+	if got, want := columnNames(view), []string{"id", "title", "post_user", "created"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := len(view.ReferencedTables), 3; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+
+	if got, want := s.Enums[0].Values, []string{"public", "private", "draft"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := s.Functions[0].ReturnType, "trigger"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func TestBuilderMySQL(t *testing.T) {
+	src := "CREATE TABLE `users` (\n" +
+		"  `id` int(11) NOT NULL AUTO_INCREMENT,\n" +
+		"  `username` varchar(50) NOT NULL COMMENT 'user name',\n" +
+		"  `updated` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  UNIQUE KEY `username` (`username`),\n" +
+		"  KEY `updated_idx` (`updated`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='Users table';\n" +
+		"CREATE TABLE `posts` (\n" +
+		"  `id` bigint NOT NULL,\n" +
+		"  `user_id` int NOT NULL,\n" +
+		"  CONSTRAINT `posts_user_id_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)\n" +
+		");\n" +
+		"ALTER TABLE `posts` ADD INDEX `posts_user_id_idx` (`user_id`);\n" +
+		"ALTER TABLE `users` RENAME COLUMN `username` TO `name`;\n"
+	b, err := NewBuilder("testdb", DialectMySQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Parse(src); err != nil {
+		t.Fatal(err)
+	}
+	s, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, err := s.FindTableByName("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := users.Comment, "Users table"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := columnNames(users), []string{"id", "name", "updated"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	id, _ := users.FindColumnByName("id")
+	if got, want := id.ExtraDef, "auto_increment"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	updated, _ := users.FindColumnByName("updated")
+	if got, want := updated.ExtraDef, "on update current_timestamp"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := indexNames(users), []string{"PRIMARY", "username", "updated_idx"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := users.Indexes[1].Columns, []string{"name"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	posts, _ := s.FindTableByName("posts")
+	if got, want := indexNames(posts), []string{"posts_user_id_idx"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := len(s.Relations), 1; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func TestBuilderSQLite(t *testing.T) {
+	src := `
+CREATE TABLE users (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  username TEXT UNIQUE NOT NULL
+);
+CREATE TABLE logs (
+  id INTEGER PRIMARY KEY,
+  user_id INTEGER REFERENCES users(id),
+  message TEXT
+);
+CREATE INDEX logs_user_id_idx ON logs(user_id);
+CREATE TRIGGER update_logs AFTER INSERT ON users
+BEGIN
+  INSERT INTO logs (user_id, message) VALUES (NEW.id, 'created');
+END;
+DROP INDEX logs_user_id_idx;
+`
+	b, err := NewBuilder("testdb", DialectSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Parse(src); err != nil {
+		t.Fatal(err)
+	}
+	s, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tableNames(s), []string{"users", "logs"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	users, _ := s.FindTableByName("users")
+	if got, want := users.Type, "table"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := len(users.Triggers), 1; got != want {
+		t.Fatalf("got %v\nwant %v", got, want)
+	}
+	logs, _ := s.FindTableByName("logs")
+	if got, want := indexNames(logs), []string{"logs_pkey"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := len(s.Relations), 1; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func TestNewBuilder(t *testing.T) {
+	if _, err := NewBuilder("testdb", "oracle"); err == nil {
+		t.Error("want error")
+	}
+}
+
+func tableNames(s *schema.Schema) []string {
+	names := []string{}
+	for _, t := range s.Tables {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func columnNames(t *schema.Table) []string {
+	names := []string{}
+	for _, c := range t.Columns {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func columnTypes(t *schema.Table) []string {
+	types := []string{}
+	for _, c := range t.Columns {
+		types = append(types, c.Name+" "+c.Type)
+	}
+	return types
+}
+
+func indexNames(t *schema.Table) []string {
+	names := []string{}
+	for _, i := range t.Indexes {
+		names = append(names, i.Name)
+	}
+	return names
+}
+
+func constraintNames(t *schema.Table) []string {
+	names := []string{}
+	for _, c := range t.Constraints {
+		names = append(names, c.Name)
+	}
+	return names
+}
@@ -0,0 +1,227 @@
+package ddl
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenKind is the kind of token
+type TokenKind int
+
+const (
+	// TokenWord is keyword or unquoted identifier
+	TokenWord TokenKind = iota
+	// TokenQuotedIdent is quoted identifier ("***", `***` or [***])
+	TokenQuotedIdent
+	// TokenString is string literal ('***' or $$***$$)
+	TokenString
+	// TokenNumber is numeric literal
+	TokenNumber
+	// TokenSymbol is operator or punctuation
+	TokenSymbol
+)
+
+// Token is the token of SQL
+type Token struct {
+	Kind TokenKind
+	// Value is the unquoted value of the token
+	Value string
+	// Pos and End are the byte offsets of the token in the source
+	Pos int
+	End int
+}
+
+// Is return whether the token is the keyword (case-insensitive)
+func (t Token) Is(keywords ...string) bool {
+	if t.Kind != TokenWord {
+		return false
+	}
+	for _, k := range keywords {
+		if strings.EqualFold(t.Value, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSymbol return whether the token is the symbol
+func (t Token) IsSymbol(s string) bool {
+	return t.Kind == TokenSymbol && t.Value == s
+}
+
+// IsIdent return whether the token can be an identifier
+func (t Token) IsIdent() bool {
+	return t.Kind == TokenWord || t.Kind == TokenQuotedIdent
+}
+
+// Tokenize split SQL of the dialect into tokens. Comments are skipped.
+// `#` starts a comment only in MySQL. In PostgreSQL, it is an operator ( e.g. `#>` ).
+func Tokenize(src, dialect string) []Token {
+	tokens := []Token{}
+	i := 0
+	for i < len(src) {
+		r, width := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case isSpace(r):
+			i += width
+		case strings.HasPrefix(src[i:], "--") || (r == '#' && dialect == DialectMySQL):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end + 1
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end + 4
+			}
+		case r == '\'':
+			value, end := scanQuoted(src, i, '\'', true)
+			tokens = append(tokens, Token{Kind: TokenString, Value: value, Pos: i, End: end})
+			i = end
+		case r == '"' || r == '`':
+			value, end := scanQuoted(src, i, byte(r), false)
+			tokens = append(tokens, Token{Kind: TokenQuotedIdent, Value: value, Pos: i, End: end})
+			i = end
+		case r == '[' && i+1 < len(src) && isIdentStart(rune(src[i+1])):
+			end := strings.IndexByte(src[i:], ']')
+			if end < 0 {
+				end = len(src) - i - 1
+			}
+			tokens = append(tokens, Token{Kind: TokenQuotedIdent, Value: src[i+1 : i+end], Pos: i, End: i + end + 1})
+			i += end + 1
+		case r == '$' && dollarTag(src[i:]) != "":
+			tag := dollarTag(src[i:])
+			end := strings.Index(src[i+len(tag):], tag)
+			if end < 0 {
+				end = len(src) - i - len(tag)
+				tokens = append(tokens, Token{Kind: TokenString, Value: src[i+len(tag):], Pos: i, End: len(src)})
+				i = len(src)
+				continue
+			}
+			tokens = append(tokens, Token{Kind: TokenString, Value: src[i+len(tag) : i+len(tag)+end], Pos: i, End: i + len(tag) + end + len(tag)})
+			i += len(tag) + end + len(tag)
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(src) && (isDigit(src[i]) || src[i] == '.' || src[i] == 'e' || src[i] == 'E') {
+				i++
+			}
+			tokens = append(tokens, Token{Kind: TokenNumber, Value: src[start:i], Pos: start, End: i})
+		case isIdentStart(r):
+			start := i
+			for i < len(src) {
+				r, width := utf8.DecodeRuneInString(src[i:])
+				if !isIdentStart(r) && !unicode.IsDigit(r) && r != '$' {
+					break
+				}
+				i += width
+			}
+			// E'***' (PostgreSQL escape string) and N'***'
+			if i-start == 1 && i < len(src) && src[i] == '\'' && strings.ContainsAny(src[start:i], "EeNn") {
+				value, end := scanQuoted(src, i, '\'', true)
+				tokens = append(tokens, Token{Kind: TokenString, Value: value, Pos: start, End: end})
+				i = end
+				continue
+			}
+			tokens = append(tokens, Token{Kind: TokenWord, Value: src[start:i], Pos: start, End: i})
+		default:
+			if strings.HasPrefix(src[i:], "::") {
+				tokens = append(tokens, Token{Kind: TokenSymbol, Value: "::", Pos: i, End: i + 2})
+				i += 2
+				continue
+			}
+			tokens = append(tokens, Token{Kind: TokenSymbol, Value: string(r), Pos: i, End: i + width})
+			i += width
+		}
+	}
+	return tokens
+}
+
+// SplitStatements split tokens into statements by `;`.
+// `;` in BEGIN ... END block of CREATE TRIGGER is not treated as the end of statement.
+func SplitStatements(tokens []Token) [][]Token {
+	stmts := [][]Token{}
+	start := 0
+	block := 0
+	for i, t := range tokens {
+		switch {
+		case t.Is("BEGIN", "CASE") && isCreateTrigger(tokens[start:i]):
+			block++
+		case t.Is("END") && block > 0:
+			block--
+		case t.IsSymbol(";") && block == 0:
+			if i > start {
+				stmts = append(stmts, tokens[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		stmts = append(stmts, tokens[start:])
+	}
+	return stmts
+}
+
+func isCreateTrigger(tokens []Token) bool {
+	if len(tokens) == 0 || !tokens[0].Is("CREATE") {
+		return false
+	}
+	for _, t := range tokens[1:] {
+		if t.Is("TRIGGER") {
+			return true
+		}
+		if t.Is("TABLE", "VIEW", "INDEX", "FUNCTION", "PROCEDURE") {
+			return false
+		}
+	}
+	return false
+}
+
+func scanQuoted(src string, start int, q byte, backslash bool) (string, int) {
+	b := new(strings.Builder)
+	i := start + 1
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case backslash && c == '\\' && i+1 < len(src):
+			b.WriteByte(src[i+1])
+			i += 2
+			continue
+		case c == q && i+1 < len(src) && src[i+1] == q:
+			b.WriteByte(q)
+			i += 2
+			continue
+		case c == q:
+			return b.String(), i + 1
+		}
+		b.WriteByte(c)
+		i++
+	}
+	return b.String(), len(src)
+}
+
+// dollarTag return the tag of dollar-quoted string ($$ or $tag$)
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '$' {
+			return s[:i+1]
+		}
+		r := rune(s[i])
+		if !isIdentStart(r) && !(i > 1 && isDigit(s[i])) {
+			return ""
+		}
+	}
+	return ""
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
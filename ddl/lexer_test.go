@@ -0,0 +1,68 @@
+package ddl
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in      string
+		dialect string
+		want    []string
+	}{
+		{
+			`CREATE TABLE "users" (id int); -- comment`,
+			DialectPostgres,
+			[]string{"CREATE", "TABLE", "users", "(", "id", "int", ")", ";"},
+		},
+		{
+			"/* comment */ COMMENT ON TABLE `users` IS 'it''s users'",
+			DialectMySQL,
+			[]string{"COMMENT", "ON", "TABLE", "users", "IS", "it's users"},
+		},
+		{
+			`SELECT $$a;b$$, $tag$c$tag$, E'd\'e', x::text, [quoted name], tags text[]`,
+			DialectPostgres,
+			[]string{"SELECT", "a;b", ",", "c", ",", "d'e", ",", "x", "::", "text", ",", "quoted name", ",", "tags", "text", "[", "]"},
+		},
+		{
+			"numeric(10, 2) # comment\nDEFAULT 1.5",
+			DialectMySQL,
+			[]string{"numeric", "(", "10", ",", "2", ")", "DEFAULT", "1.5"},
+		},
+		{
+			"CHECK ((flags # 1) > 0), path text DEFAULT (doc #>> '{a}')",
+			DialectPostgres,
+			[]string{"CHECK", "(", "(", "flags", "#", "1", ")", ">", "0", ")", ",", "path", "text", "DEFAULT", "(", "doc", "#", ">", ">", "{a}", ")"},
+		},
+	}
+	for _, tt := range tests {
+		got := []string{}
+		for _, tok := range Tokenize(tt.in, tt.dialect) {
+			got = append(got, tok.Value)
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"CREATE TABLE a (id int); CREATE TABLE b (id int);", 2},
+		{"CREATE TABLE a (id int);;\n-- comment\n", 1},
+		{"CREATE FUNCTION f() RETURNS trigger AS $$ BEGIN RETURN NEW; END; $$ LANGUAGE plpgsql; SELECT 1", 2},
+		{"CREATE TRIGGER t AFTER INSERT ON a BEGIN INSERT INTO b VALUES (CASE WHEN NEW.id > 0 THEN 1 ELSE 0 END); DELETE FROM c; END; SELECT 1;", 2},
+	}
+	for _, tt := range tests {
+		got := SplitStatements(Tokenize(tt.in, DialectPostgres))
+		if len(got) != tt.want {
+			t.Errorf("got %v\nwant %v", len(got), tt.want)
+		}
+	}
+}
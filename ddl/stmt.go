@@ -0,0 +1,260 @@
+package ddl
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	spacesRe           = regexp.MustCompile(`\s+`)
+	spaceBeforeParenRe = regexp.MustCompile(`\s+\(`)
+)
+
+// stmt is the cursor on tokens of a statement
+type stmt struct {
+	src    string
+	tokens []Token
+	pos    int
+	// fold is whether to fold unquoted identifiers to lower case (PostgreSQL)
+	fold bool
+}
+
+func (st *stmt) eof() bool {
+	return st.pos >= len(st.tokens)
+}
+
+func (st *stmt) peek() Token {
+	return st.peekN(0)
+}
+
+func (st *stmt) peekN(n int) Token {
+	i := st.pos + n
+	if i < 0 || i >= len(st.tokens) {
+		return Token{Kind: TokenSymbol, Pos: len(st.src), End: len(st.src)}
+	}
+	return st.tokens[i]
+}
+
+func (st *stmt) next() Token {
+	t := st.peek()
+	if !st.eof() {
+		st.pos++
+	}
+	return t
+}
+
+// acceptWords consumes the sequence of keywords. If the sequence does not match, nothing is consumed.
+func (st *stmt) acceptWords(words ...string) bool {
+	for i, w := range words {
+		if !st.peekN(i).Is(w) {
+			return false
+		}
+	}
+	st.pos += len(words)
+	return true
+}
+
+// ident consumes an identifier
+func (st *stmt) ident() (string, bool) {
+	t := st.peek()
+	if !t.IsIdent() {
+		return "", false
+	}
+	st.next()
+	if t.Kind == TokenWord && st.fold {
+		return strings.ToLower(t.Value), true
+	}
+	return t.Value, true
+}
+
+// name consumes a qualified name such as `schema.table`
+func (st *stmt) name() (string, bool) {
+	parts := []string{}
+	for {
+		p, ok := st.ident()
+		if !ok {
+			break
+		}
+		parts = append(parts, p)
+		if !st.peek().IsSymbol(".") {
+			break
+		}
+		st.next()
+	}
+	if len(parts) == 0 {
+		return "", false
+	}
+	return strings.Join(parts, "."), true
+}
+
+// raw return the source text of tokens[from:to]
+func (st *stmt) raw(from, to int) string {
+	if to > len(st.tokens) {
+		to = len(st.tokens)
+	}
+	if from < 0 || from >= to {
+		return ""
+	}
+	return strings.TrimSpace(st.src[st.tokens[from].Pos:st.tokens[to-1].End])
+}
+
+// closeParen return the index of the parenthesis that closes the parenthesis at i
+func (st *stmt) closeParen(i int) int {
+	depth := 0
+	for j := i; j < len(st.tokens); j++ {
+		switch {
+		case st.tokens[j].IsSymbol("("):
+			depth++
+		case st.tokens[j].IsSymbol(")"):
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return len(st.tokens) - 1
+}
+
+// splitComma split tokens between open and end (exclusive) by top-level commas
+func (st *stmt) splitComma(open, end int) []*stmt {
+	elements := []*stmt{}
+	depth := 0
+	start := open + 1
+	for i := open + 1; i < end && i < len(st.tokens); i++ {
+		switch {
+		case st.tokens[i].IsSymbol("("):
+			depth++
+		case st.tokens[i].IsSymbol(")"):
+			depth--
+		case st.tokens[i].IsSymbol(",") && depth == 0:
+			if i > start {
+				elements = append(elements, &stmt{src: st.src, tokens: st.tokens[start:i], fold: st.fold})
+			}
+			start = i + 1
+		}
+	}
+	if end > len(st.tokens) {
+		end = len(st.tokens)
+	}
+	if start < end {
+		elements = append(elements, &stmt{src: st.src, tokens: st.tokens[start:end], fold: st.fold})
+	}
+	return elements
+}
+
+// rest return the cursor on the remaining tokens
+func (st *stmt) rest() *stmt {
+	return &stmt{src: st.src, tokens: st.tokens[st.pos:], fold: st.fold}
+}
+
+// skipUntil skips tokens until one of the keywords appears at the top level
+func (st *stmt) skipUntil(keywords ...string) {
+	depth := 0
+	for !st.eof() {
+		t := st.peek()
+		switch {
+		case t.IsSymbol("("):
+			depth++
+		case t.IsSymbol(")"):
+			depth--
+		case depth == 0 && t.Is(keywords...):
+			return
+		}
+		st.next()
+	}
+}
+
+// columnList consumes a parenthesized list of columns. Expressions are returned as they are.
+func (st *stmt) columnList() []string {
+	if !st.peek().IsSymbol("(") {
+		return nil
+	}
+	end := st.closeParen(st.pos)
+	cols := []string{}
+	for _, el := range st.splitComma(st.pos, end) {
+		first := el.peek()
+		// `col`, `col DESC`, `col(10)` (MySQL prefix index), `col text_pattern_ops`
+		if first.IsIdent() && (!el.peekN(1).IsSymbol("(") || el.peekN(2).Kind == TokenNumber) && !el.peekN(1).IsSymbol(".") {
+			name, _ := el.ident()
+			cols = append(cols, name)
+			continue
+		}
+		cols = append(cols, el.raw(0, len(el.tokens)))
+	}
+	st.pos = end + 1
+	return cols
+}
+
+// dataType consumes the data type of column definition and return it with normalized spaces
+func (st *stmt) dataType() string {
+	t := st.rawDataType()
+	return spaceBeforeParenRe.ReplaceAllString(spacesRe.ReplaceAllString(t, " "), "(")
+}
+
+func (st *stmt) rawDataType() string {
+	start := st.pos
+	depth := 0
+	for !st.eof() {
+		t := st.peek()
+		switch {
+		case t.IsSymbol("("):
+			depth++
+		case t.IsSymbol(")"):
+			depth--
+		case depth == 0 && t.Is(columnConstraintKeywords...):
+			return st.raw(start, st.pos)
+		case depth == 0 && t.Is("CHARACTER") && st.peekN(1).Is("SET"):
+			return st.raw(start, st.pos)
+		}
+		st.next()
+	}
+	return st.raw(start, st.pos)
+}
+
+// selectColumns return the names of columns in the select list of view definition
+func (st *stmt) selectColumns() []string {
+	i := st.pos
+	for i < len(st.tokens) && !st.tokens[i].Is("SELECT") {
+		i++
+	}
+	if i >= len(st.tokens) {
+		return nil
+	}
+	end := i + 1
+	depth := 0
+	for ; end < len(st.tokens); end++ {
+		t := st.tokens[end]
+		if t.IsSymbol("(") {
+			depth++
+		}
+		if t.IsSymbol(")") {
+			depth--
+			if depth < 0 {
+				break
+			}
+		}
+		if depth == 0 && t.Is("FROM") {
+			break
+		}
+	}
+	cols := []string{}
+	for _, el := range st.splitComma(i, end) {
+		if el.peek().Is("DISTINCT", "ALL") {
+			el.next()
+		}
+		last := el.tokens[len(el.tokens)-1]
+		switch {
+		case last.IsSymbol("*"):
+			continue
+		case last.IsIdent():
+			el.pos = len(el.tokens) - 1
+			name, _ := el.ident()
+			cols = append(cols, name)
+		case el.peek().IsIdent() && el.peekN(1).IsSymbol("("):
+			// function call without alias
+			name, _ := el.ident()
+			cols = append(cols, name)
+		}
+	}
+	return cols
+}
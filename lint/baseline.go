@@ -0,0 +1,107 @@
+package lint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/config"
+)
+
+// Baseline is the set of existing warns to be suppressed
+type Baseline struct {
+	Warns []BaselineWarn `json:"warns"`
+}
+
+// BaselineWarn is the recorded warn of Baseline
+type BaselineWarn struct {
+	RuleID      string `json:"rule_id"`
+	Target      string `json:"target"`
+	Message     string `json:"message"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// NewBaseline return Baseline recording warns
+func NewBaseline(warns []config.RuleWarn) *Baseline {
+	b := &Baseline{Warns: []BaselineWarn{}}
+	encountered := map[string]struct{}{}
+	for _, w := range warns {
+		fp := Fingerprint(w)
+		if _, ok := encountered[fp]; ok {
+			continue
+		}
+		encountered[fp] = struct{}{}
+		b.Warns = append(b.Warns, BaselineWarn{
+			RuleID:      w.RuleID,
+			Target:      w.Target,
+			Message:     w.Message,
+			Fingerprint: fp,
+		})
+	}
+	sort.SliceStable(b.Warns, func(i, j int) bool {
+		if b.Warns[i].RuleID != b.Warns[j].RuleID {
+			return b.Warns[i].RuleID < b.Warns[j].RuleID
+		}
+		if b.Warns[i].Target != b.Warns[j].Target {
+			return b.Warns[i].Target < b.Warns[j].Target
+		}
+		return b.Warns[i].Message < b.Warns[j].Message
+	})
+	return b
+}
+
+// ReadBaseline read Baseline from the file
+func ReadBaseline(path string) (*Baseline, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer f.Close()
+	b := &Baseline{}
+	if err := json.NewDecoder(f).Decode(b); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return b, nil
+}
+
+// Write Baseline to the file
+func (b *Baseline) Write(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(b); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Filter return warns not recorded in Baseline
+func (b *Baseline) Filter(warns []config.RuleWarn) []config.RuleWarn {
+	recorded := map[string]struct{}{}
+	for _, w := range b.Warns {
+		recorded[w.Fingerprint] = struct{}{}
+	}
+	filtered := []config.RuleWarn{}
+	for _, w := range warns {
+		if _, ok := recorded[Fingerprint(w)]; ok {
+			continue
+		}
+		filtered = append(filtered, w)
+	}
+	return filtered
+}
+
+// Fingerprint return the fingerprint of the warn by the rule and the target.
+// The severity and the message are not included so that changing the severity of the rule or values in the message ( e.g. the number of columns ) does not affect Baseline.
+func Fingerprint(w config.RuleWarn) string {
+	h := sha256.Sum256([]byte(strings.Join([]string{w.RuleID, w.TargetType, w.Target, w.Discriminator}, "\x00")))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,46 @@
+package lint
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/tbls/config"
+)
+
+func TestBaseline(t *testing.T) {
+	existing := []config.RuleWarn{
+		{RuleID: "requireTableComment", Severity: config.SeverityError, TargetType: config.TargetTypeTable, Target: "users", Message: "table comment required."},
+		{RuleID: "columnCount", Severity: config.SeverityError, TargetType: config.TargetTypeTable, Target: "posts", Message: "too many columns. [12/10]"},
+		{RuleID: "requireTableComment", Severity: config.SeverityError, TargetType: config.TargetTypeTable, Target: "users", Message: "table comment required."},
+		{RuleID: "requireColumns", Severity: config.SeverityError, TargetType: config.TargetTypeTable, Target: "users", Message: "column 'created' required.", Discriminator: "created"},
+	}
+	path := filepath.Join(t.TempDir(), "lint-baseline.json")
+	if err := NewBaseline(existing).Write(path); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ReadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := []string{b.Warns[0].RuleID, b.Warns[1].RuleID, b.Warns[2].RuleID}; len(b.Warns) != 3 || got[0] != "columnCount" || got[1] != "requireColumns" || got[2] != "requireTableComment" {
+		t.Errorf("got %v\nwant [columnCount requireColumns requireTableComment]", b.Warns)
+	}
+
+	warns := []config.RuleWarn{
+		// severity changed
+		{RuleID: "requireTableComment", Severity: config.SeverityWarning, TargetType: config.TargetTypeTable, Target: "users", Message: "table comment required."},
+		// new
+		{RuleID: "requireTableComment", Severity: config.SeverityError, TargetType: config.TargetTypeTable, Target: "comments", Message: "table comment required."},
+		// new: the same rule and target, but another discriminator
+		{RuleID: "requireColumns", Severity: config.SeverityError, TargetType: config.TargetTypeTable, Target: "users", Message: "column 'updated' required.", Discriminator: "updated"},
+		// message changed
+		{RuleID: "columnCount", Severity: config.SeverityError, TargetType: config.TargetTypeTable, Target: "posts", Message: "too many columns. [13/10]"},
+		{RuleID: "requireColumns", Severity: config.SeverityError, TargetType: config.TargetTypeTable, Target: "users", Message: "column 'created' is required.", Discriminator: "created"},
+	}
+	got := b.Filter(warns)
+	want := warns[1:3]
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
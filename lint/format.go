@@ -0,0 +1,302 @@
+package lint
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/config"
+	"github.com/k1LoW/tbls/version"
+)
+
+// SupportFormats are the output formats of `tbls lint --format`
+var SupportFormats = []string{"json", "sarif", "junit", "checkstyle"}
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// Output writes warns in the format
+func Output(wr io.Writer, format string, warns []Warn) error {
+	switch format {
+	case "json":
+		return outputJSON(wr, warns)
+	case "sarif":
+		return outputSARIF(wr, warns)
+	case "junit":
+		return outputJUnit(wr, warns)
+	case "checkstyle":
+		return outputCheckstyle(wr, warns)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func outputJSON(wr io.Writer, warns []Warn) error {
+	encoder := json.NewEncoder(wr)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(warns); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+func outputSARIF(wr io.Writer, warns []Warn) error {
+	ruleIDs := []string{}
+	encountered := map[string]struct{}{}
+	results := []sarifResult{}
+	for _, w := range warns {
+		if _, ok := encountered[w.RuleID]; !ok {
+			encountered[w.RuleID] = struct{}{}
+			ruleIDs = append(ruleIDs, w.RuleID)
+		}
+		loc := sarifLocation{
+			LogicalLocations: []sarifLogicalLocation{
+				{FullyQualifiedName: w.Target, Kind: w.TargetType},
+			},
+		}
+		if w.Location != nil {
+			loc.PhysicalLocation = &sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(w.Location.Path)},
+			}
+			if w.Location.Line > 0 {
+				loc.PhysicalLocation.Region = &sarifRegion{StartLine: w.Location.Line, StartColumn: w.Location.Column}
+			}
+		}
+		results = append(results, sarifResult{
+			RuleID:    w.RuleID,
+			Level:     sarifLevel(w.Severity),
+			Message:   sarifMessage{Text: fmt.Sprintf("%s: %s", w.Target, w.Message)},
+			Locations: []sarifLocation{loc},
+		})
+	}
+	sort.Strings(ruleIDs)
+	rules := []sarifRule{}
+	for _, id := range ruleIDs {
+		rules = append(rules, sarifRule{ID: id})
+	}
+	l := sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           version.Name,
+						Version:        version.Version,
+						InformationURI: "https://github.com/k1LoW/tbls",
+						Rules:          rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+	encoder := json.NewEncoder(wr)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(l); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func sarifLevel(severity string) string {
+	switch severity {
+	case config.SeverityError:
+		return "error"
+	case config.SeverityInfo:
+		return "note"
+	default:
+		return "warning"
+	}
+}
+
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",chardata"`
+}
+
+func outputJUnit(wr io.Writer, warns []Warn) error {
+	// a test suite per rule
+	suites := []junitTestSuite{}
+	index := map[string]int{}
+	for _, w := range warns {
+		i, ok := index[w.RuleID]
+		if !ok {
+			i = len(suites)
+			index[w.RuleID] = i
+			suites = append(suites, junitTestSuite{Name: w.RuleID})
+		}
+		content := fmt.Sprintf("%s: %s", w.Target, w.Message)
+		if w.Location != nil {
+			content = fmt.Sprintf("%s\n%s", content, locationString(w.Location))
+		}
+		suites[i].Tests++
+		suites[i].Failures++
+		suites[i].TestCases = append(suites[i].TestCases, junitTestCase{
+			Name:      w.Target,
+			ClassName: w.RuleID,
+			Failure: &junitFailure{
+				Message: w.Message,
+				Type:    w.Severity,
+				Content: content,
+			},
+		})
+	}
+	return outputXML(wr, junitTestSuites{TestSuites: suites})
+}
+
+type checkstyleResult struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+func outputCheckstyle(wr io.Writer, warns []Warn) error {
+	files := []checkstyleFile{}
+	index := map[string]int{}
+	for _, w := range warns {
+		// warns without location are grouped by the target
+		name := w.Target
+		line, column := 0, 0
+		if w.Location != nil {
+			name = w.Location.Path
+			line, column = w.Location.Line, w.Location.Column
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(files)
+			index[name] = i
+			files = append(files, checkstyleFile{Name: name})
+		}
+		files[i].Errors = append(files[i].Errors, checkstyleError{
+			Line:     line,
+			Column:   column,
+			Severity: w.Severity,
+			Message:  fmt.Sprintf("%s: %s", w.Target, w.Message),
+			Source:   fmt.Sprintf("%s.%s", version.Name, w.RuleID),
+		})
+	}
+	return outputXML(wr, checkstyleResult{Version: "4.3", Files: files})
+}
+
+func outputXML(wr io.Writer, v any) error {
+	if _, err := io.WriteString(wr, xml.Header); err != nil {
+		return errors.WithStack(err)
+	}
+	encoder := xml.NewEncoder(wr)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := io.WriteString(wr, "\n"); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func locationString(l *Location) string {
+	switch {
+	case l.Line > 0 && l.Column > 0:
+		return fmt.Sprintf("%s:%d:%d", l.Path, l.Line, l.Column)
+	case l.Line > 0:
+		return fmt.Sprintf("%s:%d", l.Path, l.Line)
+	default:
+		return l.Path
+	}
+}
@@ -0,0 +1,250 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/config"
+)
+
+// Warn is the result of `tbls lint` with its location
+type Warn struct {
+	config.RuleWarn
+	Location *Location `json:"location,omitempty"`
+}
+
+// Location is the position of the warn target in the file
+type Location struct {
+	Path   string `json:"path"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+// NewWarns return Warns located in the schema.json ( if exists ) or the config file
+func NewWarns(c *config.Config, rws []config.RuleWarn) ([]Warn, error) {
+	l, err := newLocator(c)
+	if err != nil {
+		return nil, err
+	}
+	warns := []Warn{}
+	for _, rw := range rws {
+		warns = append(warns, Warn{
+			RuleWarn: rw,
+			Location: l.locate(rw),
+		})
+	}
+	return warns, nil
+}
+
+type locator struct {
+	schemaPath string
+	// key is `<target type>:<target>`
+	schemaPositions map[string]position
+	configPath      string
+	configLines     map[string]int
+}
+
+func newLocator(c *config.Config) (*locator, error) {
+	l := &locator{
+		schemaPositions: map[string]position{},
+		configLines:     map[string]int{},
+	}
+	if b, err := os.ReadFile(c.SchemaFilePath()); err == nil {
+		positions, err := schemaJSONPositions(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", c.SchemaFilePath(), err)
+		}
+		l.schemaPath = c.SchemaFilePath()
+		l.schemaPositions = positions
+	}
+	if c.Path != "" {
+		b, err := os.ReadFile(c.Path)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		lines, err := lintConfigLines(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", c.Path, err)
+		}
+		l.configPath = relPath(c.Path)
+		l.configLines = lines
+	}
+	return l, nil
+}
+
+// locate return the location of the target in the schema.json, or the location of the rule in the config file
+func (l *locator) locate(w config.RuleWarn) *Location {
+	keys := []string{fmt.Sprintf("%s:%s", w.TargetType, w.Target)}
+	switch w.TargetType {
+	case config.TargetTypeColumn, config.TargetTypeIndex, config.TargetTypeConstraint, config.TargetTypeTrigger:
+		if i := strings.LastIndex(w.Target, "."); i > 0 {
+			keys = append(keys, fmt.Sprintf("%s:%s", config.TargetTypeTable, w.Target[:i]))
+		}
+	case config.TargetTypeRelation:
+		keys = append(keys, fmt.Sprintf("%s:%s", config.TargetTypeTable, w.Target))
+	}
+	for _, k := range keys {
+		if p, ok := l.schemaPositions[k]; ok {
+			return &Location{Path: l.schemaPath, Line: p.line, Column: p.column}
+		}
+	}
+	if l.configPath == "" {
+		return nil
+	}
+	return &Location{Path: l.configPath, Line: l.configLines[w.RuleID]}
+}
+
+type position struct {
+	line   int
+	column int
+}
+
+// schemaJSONPositions return the positions of the names of tables, columns, indexes, constraints and triggers in schema.json.
+// The column is needed because schema.json generated by `tbls doc` is not indented.
+func schemaJSONPositions(b []byte) (map[string]position, error) {
+	positions := map[string]position{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	table := ""
+	err := walkJSON(dec, []string{}, func(path []string, v string, offset int64) {
+		if path[len(path)-1] != "name" || path[0] != "tables" {
+			return
+		}
+		// the position of `"name"` key
+		start := int(offset)
+		for start < len(b) && bytes.IndexByte([]byte(", \t\r\n"), b[start]) >= 0 {
+			start++
+		}
+		p := position{
+			line:   bytes.Count(b[:start], []byte("\n")) + 1,
+			column: start - bytes.LastIndexByte(b[:start], '\n'),
+		}
+		switch len(path) {
+		case 3: // tables[].name
+			table = v
+			positions[fmt.Sprintf("%s:%s", config.TargetTypeTable, v)] = p
+		case 5: // tables[].columns[].name
+			var tt string
+			switch path[2] {
+			case "columns":
+				tt = config.TargetTypeColumn
+			case "indexes":
+				tt = config.TargetTypeIndex
+			case "constraints":
+				tt = config.TargetTypeConstraint
+			case "triggers":
+				tt = config.TargetTypeTrigger
+			default:
+				return
+			}
+			positions[fmt.Sprintf("%s:%s.%s", tt, table, v)] = p
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
+
+// walkJSON walk JSON values and call fn with the path of each string value and the offset before its key. Array elements are represented as `[]` in the path.
+func walkJSON(dec *json.Decoder, path []string, fn func(path []string, v string, offset int64)) error {
+	return walkJSONValue(dec, path, 0, fn)
+}
+
+func walkJSONValue(dec *json.Decoder, path []string, offset int64, fn func(path []string, v string, offset int64)) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			for dec.More() {
+				offset := dec.InputOffset()
+				k, err := dec.Token()
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				key, ok := k.(string)
+				if !ok {
+					return fmt.Errorf("invalid key: %v", k)
+				}
+				if err := walkJSONValue(dec, append(path, key), offset, fn); err != nil {
+					return err
+				}
+			}
+		case '[':
+			for dec.More() {
+				if err := walkJSONValue(dec, append(path, "[]"), dec.InputOffset(), fn); err != nil {
+					return err
+				}
+			}
+		}
+		// consume the closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return errors.WithStack(err)
+		}
+	case string:
+		if len(path) > 0 {
+			fn(path, v, offset)
+		}
+	}
+	return nil
+}
+
+// lintConfigLines return the line numbers of the rules in `lint:` section of the config file
+func lintConfigLines(b []byte) (map[string]int, error) {
+	lines := map[string]int{}
+	f, err := parser.ParseBytes(b, 0)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, d := range f.Docs {
+		lint := findMappingValue(d.Body, "lint")
+		if lint == nil {
+			continue
+		}
+		for _, mv := range mappingValues(lint.Value) {
+			lines[mv.Key.String()] = mv.GetToken().Position.Line
+		}
+	}
+	return lines, nil
+}
+
+func findMappingValue(n ast.Node, key string) *ast.MappingValueNode {
+	for _, mv := range mappingValues(n) {
+		if mv.Key.String() == key {
+			return mv
+		}
+	}
+	return nil
+}
+
+func mappingValues(n ast.Node) []*ast.MappingValueNode {
+	switch v := n.(type) {
+	case *ast.MappingNode:
+		return v.Values
+	case *ast.MappingValueNode:
+		return []*ast.MappingValueNode{v}
+	}
+	return nil
+}
+
+func relPath(p string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return p
+	}
+	rel, err := filepath.Rel(wd, p)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return p
+	}
+	return rel
+}
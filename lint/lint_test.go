@@ -0,0 +1,92 @@
+package lint
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/tbls/config"
+	"github.com/k1LoW/tbls/version"
+	"github.com/tenntenn/golden"
+)
+
+func TestNewWarns(t *testing.T) {
+	dir := filepath.Join(testdataDir(), "lint")
+	c, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Load(filepath.Join(dir, "tbls.yml"), config.DocPath(dir)); err != nil {
+		t.Fatal(err)
+	}
+	rws := []config.RuleWarn{
+		{RuleID: "requireTableComment", TargetType: config.TargetTypeTable, Target: "posts", Message: "table comment required."},
+		{RuleID: "requireColumnComment", TargetType: config.TargetTypeColumn, Target: "users.name", Message: "column comment required."},
+		{RuleID: "requireIndexComment", TargetType: config.TargetTypeIndex, Target: "users.users_pkey", Message: "index comment required."},
+		{RuleID: "requireColumnComment", TargetType: config.TargetTypeColumn, Target: "users.unknown", Message: "column comment required."},
+		{RuleID: "unrelatedTable", TargetType: config.TargetTypeSchema, Target: "testdb", Message: "unrelated (isolated) table exists. [users posts]"},
+	}
+	schemaPath := filepath.Join(dir, "schema.json")
+	want := []*Location{
+		{Path: schemaPath, Line: 31, Column: 7},
+		{Path: schemaPath, Line: 14, Column: 11},
+		{Path: schemaPath, Line: 21, Column: 11},
+		{Path: schemaPath, Line: 5, Column: 7},
+		{Path: relPath(c.Path), Line: 8},
+	}
+	got, err := NewWarns(c, rws)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, w := range got {
+		if diff := cmp.Diff(w.Location, want[i]); diff != "" {
+			t.Errorf("%s: %s", w.Target, diff)
+		}
+	}
+}
+
+func TestOutput(t *testing.T) {
+	v := version.Version
+	version.Version = "0.0.0"
+	t.Cleanup(func() {
+		version.Version = v
+	})
+	warns := []Warn{
+		{
+			RuleWarn: config.RuleWarn{RuleID: "requireTableComment", Severity: config.SeverityWarning, TargetType: config.TargetTypeTable, Target: "posts", Message: "table comment required."},
+			Location: &Location{Path: "dbdoc/schema.json", Line: 1, Column: 120},
+		},
+		{
+			RuleWarn: config.RuleWarn{RuleID: "requireColumnComment", Severity: config.SeverityError, TargetType: config.TargetTypeColumn, Target: "posts.title", Message: "column comment required."},
+			Location: &Location{Path: "dbdoc/schema.json", Line: 1, Column: 200},
+		},
+		{
+			RuleWarn: config.RuleWarn{RuleID: "unrelatedTable", Severity: config.SeverityInfo, TargetType: config.TargetTypeSchema, Target: "testdb", Message: "unrelated (isolated) table exists. [logs]"},
+		},
+	}
+	for _, format := range SupportFormats {
+		t.Run(format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := Output(buf, format, warns); err != nil {
+				t.Fatal(err)
+			}
+			got := buf.String()
+			f := "lint_test_" + format
+			if os.Getenv("UPDATE_GOLDEN") != "" {
+				golden.Update(t, testdataDir(), f, got)
+				return
+			}
+			if diff := golden.Diff(t, testdataDir(), f, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func testdataDir() string {
+	wd, _ := os.Getwd()
+	dir, _ := filepath.Abs(filepath.Join(filepath.Dir(wd), "testdata"))
+	return dir
+}
@@ -0,0 +1,660 @@
+package migrate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/k1LoW/tbls/schema"
+	"github.com/k1LoW/tbls/schema/diff"
+	"github.com/samber/lo"
+)
+
+// Dialect is the SQL dialect of the generated statements
+type Dialect string
+
+const (
+	Postgres Dialect = "postgres"
+	MySQL    Dialect = "mysql"
+	SQLite   Dialect = "sqlite"
+)
+
+// ParseDialect parse dialect name. Driver names such as `mariadb` and `sqlite3` are also accepted.
+func ParseDialect(name string) (Dialect, error) {
+	switch strings.ToLower(name) {
+	case "postgres", "postgresql", "pg":
+		return Postgres, nil
+	case "mysql", "mariadb", "maria":
+		return MySQL, nil
+	case "sqlite", "sqlite3":
+		return SQLite, nil
+	}
+	return "", fmt.Errorf("unsupported dialect '%s'. supported dialects are postgres, mysql and sqlite", name)
+}
+
+type phase int
+
+const (
+	phaseDropForeignKey phase = iota
+	phaseDropConstraint
+	phaseDropTrigger
+	phaseDropIndex
+	phaseDropView
+	phaseDropTable
+	phaseDropFunction
+	phaseDropEnum
+	phaseCreateEnum
+	phaseCreateFunction
+	phaseCreateTable
+	phaseAlterColumn
+	phaseCreateView
+	phaseCreateIndex
+	phaseAddConstraint
+	phaseAddForeignKey
+	phaseCreateTrigger
+	phaseComment
+)
+
+type planner struct {
+	dialect Dialect
+	from    *schema.Schema
+	to      *schema.Schema
+	// useDef is whether the definitions of `to` are written in the same dialect
+	useDef bool
+	stmts  map[phase][]string
+}
+
+// Plan generates SQL statements that migrate schema `from` to schema `to`.
+// Operations that can not be expressed in the dialect are output as SQL comments.
+func Plan(from, to *schema.Schema, dialect Dialect) ([]string, error) {
+	if _, err := ParseDialect(string(dialect)); err != nil {
+		return nil, err
+	}
+	p := &planner{
+		dialect: dialect,
+		from:    from,
+		to:      to,
+		stmts:   map[phase][]string{},
+	}
+	if to.Driver != nil {
+		if d, err := ParseDialect(to.Driver.Name); err == nil && d == dialect {
+			p.useDef = true
+		}
+	}
+	d := diff.Compare(from, to)
+	for _, c := range d.Changes {
+		if err := p.plan(c); err != nil {
+			return nil, err
+		}
+	}
+	phases := lo.Keys(p.stmts)
+	sort.Slice(phases, func(i, j int) bool { return phases[i] < phases[j] })
+	stmts := []string{}
+	for _, ph := range phases {
+		stmts = append(stmts, p.stmts[ph]...)
+	}
+	return stmts, nil
+}
+
+func (p *planner) add(ph phase, stmt string) {
+	p.stmts[ph] = append(p.stmts[ph], stmt)
+}
+
+func (p *planner) unsupported(ph phase, format string, a ...any) {
+	p.add(ph, fmt.Sprintf("-- %s", fmt.Sprintf(format, a...)))
+}
+
+func (p *planner) plan(c *diff.Change) error {
+	switch c.Kind {
+	case diff.KindTable:
+		return p.planTable(c)
+	case diff.KindColumn:
+		return p.planColumn(c)
+	case diff.KindIndex:
+		return p.planIndex(c)
+	case diff.KindConstraint:
+		return p.planConstraint(c)
+	case diff.KindTrigger:
+		return p.planTrigger(c)
+	case diff.KindFunction:
+		return p.planFunction(c)
+	case diff.KindEnum:
+		return p.planEnum(c)
+	}
+	return nil
+}
+
+func (p *planner) planTable(c *diff.Change) error {
+	switch c.Op {
+	case diff.OpRemoved:
+		ft, err := p.from.FindTableByName(c.Name)
+		if err != nil {
+			return err
+		}
+		p.dropTable(ft)
+	case diff.OpAdded:
+		tt, err := p.to.FindTableByName(c.Name)
+		if err != nil {
+			return err
+		}
+		if isView(tt) {
+			p.createView(tt)
+			return nil
+		}
+		p.createTable(tt)
+	case diff.OpModified:
+		ft, err := p.from.FindTableByName(c.Name)
+		if err != nil {
+			return err
+		}
+		tt, err := p.to.FindTableByName(c.Name)
+		if err != nil {
+			return err
+		}
+		if f := findField(c, "type"); f != nil {
+			p.unsupported(phaseCreateTable, "type of %s changed from %s to %s; recreate it manually", tt.Name, f.From, f.To)
+			return nil
+		}
+		if findField(c, "def") != nil && isView(tt) {
+			p.dropTable(ft)
+			p.createView(tt)
+		}
+		if findField(c, "comment") != nil {
+			p.commentTable(tt)
+		}
+	}
+	return nil
+}
+
+func (p *planner) planColumn(c *diff.Change) error {
+	tt, err := p.to.FindTableByName(c.Table)
+	if err != nil {
+		return err
+	}
+	switch c.Op {
+	case diff.OpRemoved:
+		p.add(phaseAlterColumn, fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", p.quote(tt.Name), p.quote(c.Name)))
+	case diff.OpAdded:
+		tc, err := tt.FindColumnByName(c.Name)
+		if err != nil {
+			return err
+		}
+		p.add(phaseAlterColumn, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", p.quote(tt.Name), p.columnDef(tc)))
+		if tc.Comment != "" {
+			p.commentColumn(tt, tc)
+		}
+	case diff.OpModified:
+		tc, err := tt.FindColumnByName(c.Name)
+		if err != nil {
+			return err
+		}
+		p.modifyColumn(tt, tc, c)
+	}
+	return nil
+}
+
+func (p *planner) modifyColumn(t *schema.Table, col *schema.Column, c *diff.Change) {
+	fields := lo.FilterMap(c.Fields, func(f *diff.Field, _ int) (string, bool) {
+		return f.Name, f.Name != "labels"
+	})
+	if len(fields) == 0 {
+		return
+	}
+	switch p.dialect {
+	case Postgres:
+		prefix := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s", p.quote(t.Name), p.quote(col.Name))
+		for _, f := range fields {
+			switch f {
+			case "type":
+				p.add(phaseAlterColumn, fmt.Sprintf("%s TYPE %s;", prefix, col.Type))
+			case "nullable":
+				if col.Nullable {
+					p.add(phaseAlterColumn, fmt.Sprintf("%s DROP NOT NULL;", prefix))
+				} else {
+					p.add(phaseAlterColumn, fmt.Sprintf("%s SET NOT NULL;", prefix))
+				}
+			case "default":
+				if col.Default.Valid {
+					p.add(phaseAlterColumn, fmt.Sprintf("%s SET DEFAULT %s;", prefix, col.Default.String))
+				} else {
+					p.add(phaseAlterColumn, fmt.Sprintf("%s DROP DEFAULT;", prefix))
+				}
+			case "comment":
+				p.commentColumn(t, col)
+			case "extra_def":
+				p.unsupported(phaseAlterColumn, "extra definition of %s.%s changed to '%s'; alter it manually", t.Name, col.Name, col.ExtraDef)
+			}
+		}
+	case MySQL:
+		p.add(phaseAlterColumn, fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;", p.quote(t.Name), p.columnDef(col)))
+	case SQLite:
+		p.unsupported(phaseAlterColumn, "sqlite does not support altering column %s.%s (%s); rebuild the table", t.Name, col.Name, strings.Join(fields, ", "))
+	}
+}
+
+func (p *planner) planIndex(c *diff.Change) error {
+	if c.Op != diff.OpAdded {
+		ft, err := p.from.FindTableByName(c.Table)
+		if err != nil {
+			return err
+		}
+		fi, ok := lo.Find(ft.Indexes, func(i *schema.Index) bool { return i.Name == c.Name })
+		if ok && !isConstraintIndex(ft, fi) {
+			p.dropIndex(ft, fi)
+		}
+	}
+	if c.Op != diff.OpRemoved {
+		tt, err := p.to.FindTableByName(c.Table)
+		if err != nil {
+			return err
+		}
+		ti, ok := lo.Find(tt.Indexes, func(i *schema.Index) bool { return i.Name == c.Name })
+		if ok && !isConstraintIndex(tt, ti) {
+			p.createIndex(tt, ti)
+		}
+	}
+	return nil
+}
+
+func (p *planner) planConstraint(c *diff.Change) error {
+	if c.Op == diff.OpModified && findField(c, "comment") != nil && len(c.Fields) == 1 {
+		return nil
+	}
+	if c.Op != diff.OpAdded {
+		ft, err := p.from.FindTableByName(c.Table)
+		if err != nil {
+			return err
+		}
+		if fc, ok := lo.Find(ft.Constraints, func(cs *schema.Constraint) bool { return cs.Name == c.Name }); ok {
+			p.dropConstraint(ft, fc)
+		}
+	}
+	if c.Op != diff.OpRemoved {
+		tt, err := p.to.FindTableByName(c.Table)
+		if err != nil {
+			return err
+		}
+		if tc, ok := lo.Find(tt.Constraints, func(cs *schema.Constraint) bool { return cs.Name == c.Name }); ok {
+			p.addConstraint(tt, tc)
+		}
+	}
+	return nil
+}
+
+func (p *planner) planTrigger(c *diff.Change) error {
+	if c.Op == diff.OpModified && findField(c, "def") == nil {
+		return nil
+	}
+	if c.Op != diff.OpAdded {
+		ft, err := p.from.FindTableByName(c.Table)
+		if err != nil {
+			return err
+		}
+		if fg, ok := lo.Find(ft.Triggers, func(g *schema.Trigger) bool { return g.Name == c.Name }); ok {
+			p.dropTrigger(ft, fg)
+		}
+	}
+	if c.Op != diff.OpRemoved {
+		tt, err := p.to.FindTableByName(c.Table)
+		if err != nil {
+			return err
+		}
+		if tg, ok := lo.Find(tt.Triggers, func(g *schema.Trigger) bool { return g.Name == c.Name }); ok {
+			p.createTrigger(tt, tg)
+		}
+	}
+	return nil
+}
+
+func (p *planner) planFunction(c *diff.Change) error {
+	switch c.Op {
+	case diff.OpRemoved:
+		ff, ok := lo.Find(p.from.Functions, func(f *schema.Function) bool { return functionName(f) == c.Name })
+		if ok {
+			p.dropFunction(ff)
+		}
+	case diff.OpAdded:
+		p.unsupported(phaseCreateFunction, "definition of function %s is not available; create it manually", c.Name)
+	default:
+		// the function can not be recreated, so do not drop it
+		p.unsupported(phaseCreateFunction, "definition of function %s is not available; alter it manually", c.Name)
+	}
+	return nil
+}
+
+func (p *planner) planEnum(c *diff.Change) error {
+	if p.dialect != Postgres {
+		p.unsupported(phaseCreateEnum, "enum %s %s; %s does not support enum types", c.Name, c.Op, p.dialect)
+		return nil
+	}
+	switch c.Op {
+	case diff.OpRemoved:
+		p.add(phaseDropEnum, fmt.Sprintf("DROP TYPE %s;", p.quote(c.Name)))
+	case diff.OpAdded:
+		te, ok := lo.Find(p.to.Enums, func(e *schema.Enum) bool { return e.Name == c.Name })
+		if !ok {
+			return fmt.Errorf("not found enum '%s'", c.Name)
+		}
+		values := lo.Map(te.Values, func(v string, _ int) string { return quoteString(v) })
+		p.add(phaseCreateEnum, fmt.Sprintf("CREATE TYPE %s AS ENUM (%s);", p.quote(te.Name), strings.Join(values, ", ")))
+	case diff.OpModified:
+		fe, _ := lo.Find(p.from.Enums, func(e *schema.Enum) bool { return e.Name == c.Name })
+		te, _ := lo.Find(p.to.Enums, func(e *schema.Enum) bool { return e.Name == c.Name })
+		if fe == nil || te == nil {
+			return fmt.Errorf("not found enum '%s'", c.Name)
+		}
+		for _, v := range lo.Without(te.Values, fe.Values...) {
+			p.add(phaseCreateEnum, fmt.Sprintf("ALTER TYPE %s ADD VALUE %s;", p.quote(te.Name), quoteString(v)))
+		}
+		if removed := lo.Without(fe.Values, te.Values...); len(removed) > 0 {
+			p.unsupported(phaseCreateEnum, "postgres does not support removing values (%s) from enum %s; recreate the type", strings.Join(removed, ", "), te.Name)
+		}
+	}
+	return nil
+}
+
+func (p *planner) dropTable(t *schema.Table) {
+	if isView(t) {
+		p.add(phaseDropView, fmt.Sprintf("DROP %s %s;", strings.ToUpper(t.Type), p.quote(t.Name)))
+		return
+	}
+	p.add(phaseDropTable, fmt.Sprintf("DROP TABLE %s;", p.quote(t.Name)))
+}
+
+func (p *planner) createView(t *schema.Table) {
+	if p.useDef && hasPrefixFold(t.Def, "CREATE") {
+		p.add(phaseCreateView, statement(t.Def))
+		return
+	}
+	p.unsupported(phaseCreateView, "definition of %s %s is not available; create it manually", strings.ToLower(t.Type), t.Name)
+}
+
+func (p *planner) createTable(t *schema.Table) {
+	if p.useDef && hasPrefixFold(t.Def, "CREATE") {
+		// The table definition includes columns and constraints
+		p.add(phaseCreateTable, statement(t.Def))
+		for _, i := range t.Indexes {
+			if hasPrefixFold(i.Def, "CREATE") {
+				p.add(phaseCreateIndex, statement(i.Def))
+			}
+		}
+		for _, g := range t.Triggers {
+			p.createTrigger(t, g)
+		}
+		return
+	}
+	lines := []string{}
+	for _, c := range t.Columns {
+		lines = append(lines, p.columnDef(c))
+	}
+	for _, cs := range t.Constraints {
+		if constraintType(cs) == "FOREIGN KEY" && p.dialect != SQLite {
+			p.addConstraint(t, cs)
+			continue
+		}
+		if def := p.constraintDef(cs); def != "" {
+			lines = append(lines, p.constraintClause(cs, def))
+		}
+	}
+	stmt := fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", p.quote(t.Name), strings.Join(lines, ",\n  "))
+	if p.dialect == MySQL && t.Comment != "" {
+		stmt += fmt.Sprintf(" COMMENT = %s", quoteString(t.Comment))
+	}
+	p.add(phaseCreateTable, stmt+";")
+	for _, i := range t.Indexes {
+		if !isConstraintIndex(t, i) {
+			p.createIndex(t, i)
+		}
+	}
+	for _, g := range t.Triggers {
+		p.createTrigger(t, g)
+	}
+	if p.dialect == Postgres {
+		if t.Comment != "" {
+			p.commentTable(t)
+		}
+		for _, c := range t.Columns {
+			if c.Comment != "" {
+				p.commentColumn(t, c)
+			}
+		}
+	}
+}
+
+func (p *planner) columnDef(c *schema.Column) string {
+	def := fmt.Sprintf("%s %s", p.quote(c.Name), c.Type)
+	if !c.Nullable {
+		def += " NOT NULL"
+	}
+	if c.Default.Valid {
+		def += fmt.Sprintf(" DEFAULT %s", c.Default.String)
+	}
+	if p.useDef && c.ExtraDef != "" {
+		def += fmt.Sprintf(" %s", c.ExtraDef)
+	}
+	if p.dialect == MySQL && c.Comment != "" {
+		def += fmt.Sprintf(" COMMENT %s", quoteString(c.Comment))
+	}
+	return def
+}
+
+func (p *planner) commentTable(t *schema.Table) {
+	switch p.dialect {
+	case Postgres:
+		p.add(phaseComment, fmt.Sprintf("COMMENT ON TABLE %s IS %s;", p.quote(t.Name), quoteNullableString(t.Comment)))
+	case MySQL:
+		p.add(phaseComment, fmt.Sprintf("ALTER TABLE %s COMMENT = %s;", p.quote(t.Name), quoteString(t.Comment)))
+	}
+}
+
+func (p *planner) commentColumn(t *schema.Table, c *schema.Column) {
+	// MySQL column comments are part of the column definition
+	if p.dialect == Postgres {
+		p.add(phaseComment, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", p.quote(t.Name), p.quote(c.Name), quoteNullableString(c.Comment)))
+	}
+}
+
+func (p *planner) createIndex(t *schema.Table, i *schema.Index) {
+	if strings.Contains(strings.ToUpper(i.Def), "PRIMARY KEY") {
+		return
+	}
+	if p.useDef && hasPrefixFold(i.Def, "CREATE") {
+		p.add(phaseCreateIndex, statement(i.Def))
+		return
+	}
+	unique := ""
+	if strings.Contains(strings.ToUpper(i.Def), "UNIQUE") {
+		unique = "UNIQUE "
+	}
+	columns := lo.Map(i.Columns, func(c string, _ int) string { return p.quote(c) })
+	p.add(phaseCreateIndex, fmt.Sprintf("CREATE %sINDEX %s ON %s (%s);", unique, p.quote(i.Name), p.quote(t.Name), strings.Join(columns, ", ")))
+}
+
+func (p *planner) dropIndex(t *schema.Table, i *schema.Index) {
+	if strings.Contains(strings.ToUpper(i.Def), "PRIMARY KEY") {
+		return
+	}
+	if p.dialect == MySQL {
+		p.add(phaseDropIndex, fmt.Sprintf("DROP INDEX %s ON %s;", p.quote(i.Name), p.quote(t.Name)))
+		return
+	}
+	p.add(phaseDropIndex, fmt.Sprintf("DROP INDEX %s;", p.quote(i.Name)))
+}
+
+func (p *planner) constraintDef(cs *schema.Constraint) string {
+	if p.useDef && cs.Def != "" {
+		return cs.Def
+	}
+	columns := strings.Join(lo.Map(cs.Columns, func(c string, _ int) string { return p.quote(c) }), ", ")
+	switch constraintType(cs) {
+	case "PRIMARY KEY", "UNIQUE":
+		if len(cs.Columns) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%s (%s)", constraintType(cs), columns)
+	case "FOREIGN KEY":
+		if len(cs.Columns) == 0 || cs.ReferencedTable == nil {
+			return ""
+		}
+		referenced := strings.Join(lo.Map(cs.ReferencedColumns, func(c string, _ int) string { return p.quote(c) }), ", ")
+		return fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)", columns, p.quote(*cs.ReferencedTable), referenced)
+	case "CHECK":
+		// CHECK expressions are written in the standard SQL in most cases
+		return cs.Def
+	}
+	return ""
+}
+
+func (p *planner) constraintClause(cs *schema.Constraint, def string) string {
+	if !hasName(cs) || (p.dialect == MySQL && constraintType(cs) == "PRIMARY KEY") {
+		return def
+	}
+	return fmt.Sprintf("CONSTRAINT %s %s", p.quote(cs.Name), def)
+}
+
+func (p *planner) addConstraint(t *schema.Table, cs *schema.Constraint) {
+	ph := phaseAddConstraint
+	if constraintType(cs) == "FOREIGN KEY" {
+		ph = phaseAddForeignKey
+	}
+	if p.dialect == SQLite {
+		p.unsupported(ph, "sqlite does not support adding constraint %s to %s; rebuild the table", cs.Name, t.Name)
+		return
+	}
+	def := p.constraintDef(cs)
+	if def == "" {
+		p.unsupported(ph, "definition of constraint %s on %s is not available; add it manually", cs.Name, t.Name)
+		return
+	}
+	p.add(ph, fmt.Sprintf("ALTER TABLE %s ADD %s;", p.quote(t.Name), p.constraintClause(cs, def)))
+}
+
+func (p *planner) dropConstraint(t *schema.Table, cs *schema.Constraint) {
+	ph := phaseDropConstraint
+	if constraintType(cs) == "FOREIGN KEY" {
+		ph = phaseDropForeignKey
+	}
+	if p.dialect == SQLite {
+		p.unsupported(ph, "sqlite does not support dropping constraint %s from %s; rebuild the table", cs.Name, t.Name)
+		return
+	}
+	if !hasName(cs) {
+		p.unsupported(ph, "constraint '%s' on %s has no name; drop it manually", cs.Def, t.Name)
+		return
+	}
+	prefix := fmt.Sprintf("ALTER TABLE %s", p.quote(t.Name))
+	if p.dialect == MySQL {
+		switch constraintType(cs) {
+		case "PRIMARY KEY":
+			p.add(ph, fmt.Sprintf("%s DROP PRIMARY KEY;", prefix))
+		case "FOREIGN KEY":
+			p.add(ph, fmt.Sprintf("%s DROP FOREIGN KEY %s;", prefix, p.quote(cs.Name)))
+		case "UNIQUE":
+			p.add(ph, fmt.Sprintf("%s DROP INDEX %s;", prefix, p.quote(cs.Name)))
+		default:
+			p.add(ph, fmt.Sprintf("%s DROP CHECK %s;", prefix, p.quote(cs.Name)))
+		}
+		return
+	}
+	p.add(ph, fmt.Sprintf("%s DROP CONSTRAINT %s;", prefix, p.quote(cs.Name)))
+}
+
+func (p *planner) createTrigger(t *schema.Table, g *schema.Trigger) {
+	if p.useDef && hasPrefixFold(g.Def, "CREATE") {
+		p.add(phaseCreateTrigger, statement(g.Def))
+		return
+	}
+	p.unsupported(phaseCreateTrigger, "definition of trigger %s on %s is not available; create it manually", g.Name, t.Name)
+}
+
+func (p *planner) dropTrigger(t *schema.Table, g *schema.Trigger) {
+	if p.dialect == Postgres {
+		p.add(phaseDropTrigger, fmt.Sprintf("DROP TRIGGER %s ON %s;", p.quote(g.Name), p.quote(t.Name)))
+		return
+	}
+	p.add(phaseDropTrigger, fmt.Sprintf("DROP TRIGGER %s;", p.quote(g.Name)))
+}
+
+func (p *planner) dropFunction(f *schema.Function) {
+	typ := "FUNCTION"
+	if strings.EqualFold(f.Type, "PROCEDURE") {
+		typ = "PROCEDURE"
+	}
+	switch p.dialect {
+	case Postgres:
+		p.add(phaseDropFunction, fmt.Sprintf("DROP %s %s(%s);", typ, p.quote(f.Name), f.Arguments))
+	case MySQL:
+		p.add(phaseDropFunction, fmt.Sprintf("DROP %s %s;", typ, p.quote(f.Name)))
+	default:
+		p.unsupported(phaseDropFunction, "%s does not support functions; drop %s manually", p.dialect, f.Name)
+	}
+}
+
+// quote quotes the identifier. The identifier qualified with the schema name (e.g. `public.users`) is quoted per part.
+func (p *planner) quote(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if p.dialect == MySQL {
+			parts[i] = fmt.Sprintf("`%s`", strings.ReplaceAll(part, "`", "``"))
+		} else {
+			parts[i] = fmt.Sprintf(`"%s"`, strings.ReplaceAll(part, `"`, `""`))
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+func quoteString(str string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(str, "'", "''"))
+}
+
+func quoteNullableString(str string) string {
+	if str == "" {
+		return "NULL"
+	}
+	return quoteString(str)
+}
+
+func statement(def string) string {
+	return strings.TrimRight(strings.TrimSpace(def), ";") + ";"
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	s = strings.TrimSpace(s)
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+func isView(t *schema.Table) bool {
+	return strings.Contains(strings.ToUpper(t.Type), "VIEW")
+}
+
+func hasName(cs *schema.Constraint) bool {
+	return cs.Name != "" && cs.Name != "-"
+}
+
+// constraintType return the type of the constraint. If the type is empty, it is detected from the definition.
+func constraintType(cs *schema.Constraint) string {
+	t := strings.ToUpper(cs.Type)
+	if t == "" {
+		t = strings.ToUpper(cs.Def)
+	}
+	for _, typ := range []string{"PRIMARY KEY", "FOREIGN KEY", "UNIQUE", "CHECK"} {
+		if strings.HasPrefix(t, typ) {
+			return typ
+		}
+	}
+	return t
+}
+
+// isConstraintIndex return whether the index is created by the constraint.
+func isConstraintIndex(t *schema.Table, i *schema.Index) bool {
+	return lo.ContainsBy(t.Constraints, func(cs *schema.Constraint) bool { return cs.Name == i.Name })
+}
+
+func findField(c *diff.Change, name string) *diff.Field {
+	f, _ := lo.Find(c.Fields, func(f *diff.Field) bool { return f.Name == name })
+	return f
+}
+
+func functionName(f *schema.Function) string {
+	return fmt.Sprintf("%s(%s)", f.Name, f.Arguments)
+}
@@ -0,0 +1,273 @@
+package migrate
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/tbls/schema"
+	"github.com/k1LoW/tbls/testutil"
+	"github.com/samber/lo"
+)
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		modify  func(s *schema.Schema)
+		want    []string
+	}{
+		{
+			"no changes",
+			Postgres,
+			func(s *schema.Schema) {},
+			[]string{},
+		},
+		{
+			"add column",
+			Postgres,
+			func(s *schema.Schema) {
+				s.Tables[0].Columns = append(s.Tables[0].Columns, &schema.Column{Name: "a3", Type: "varchar(255)", Default: sql.NullString{String: "''", Valid: true}, Comment: "column a3"})
+			},
+			[]string{
+				`ALTER TABLE "a" ADD COLUMN "a3" varchar(255) NOT NULL DEFAULT '';`,
+				`COMMENT ON COLUMN "a"."a3" IS 'column a3';`,
+			},
+		},
+		{
+			"add column (mysql)",
+			MySQL,
+			func(s *schema.Schema) {
+				s.Tables[0].Columns = append(s.Tables[0].Columns, &schema.Column{Name: "a3", Type: "varchar(255)", Nullable: true, Comment: "column a3"})
+			},
+			[]string{
+				"ALTER TABLE `a` ADD COLUMN `a3` varchar(255) COMMENT 'column a3';",
+			},
+		},
+		{
+			"modify column",
+			Postgres,
+			func(s *schema.Schema) {
+				c, _ := s.Tables[0].FindColumnByName("a2")
+				c.Type = "varchar(10)"
+				c.Nullable = true
+			},
+			[]string{
+				`ALTER TABLE "a" ALTER COLUMN "a2" TYPE varchar(10);`,
+				`ALTER TABLE "a" ALTER COLUMN "a2" DROP NOT NULL;`,
+			},
+		},
+		{
+			"modify column (mysql)",
+			MySQL,
+			func(s *schema.Schema) {
+				c, _ := s.Tables[0].FindColumnByName("a2")
+				c.Type = "varchar(10)"
+			},
+			[]string{
+				"ALTER TABLE `a` MODIFY COLUMN `a2` varchar(10) NOT NULL COMMENT 'column a2';",
+			},
+		},
+		{
+			"modify column (sqlite)",
+			SQLite,
+			func(s *schema.Schema) {
+				c, _ := s.Tables[0].FindColumnByName("a2")
+				c.Type = "varchar(10)"
+			},
+			[]string{
+				"-- sqlite does not support altering column a.a2 (type); rebuild the table",
+			},
+		},
+		{
+			"drop table and view",
+			Postgres,
+			func(s *schema.Schema) {
+				s.Tables = s.Tables[:1]
+				s.Relations = nil
+				s.Viewpoints = nil
+			},
+			[]string{
+				`DROP VIEW "view";`,
+				`DROP TABLE "b";`,
+			},
+		},
+		{
+			"create table",
+			Postgres,
+			func(s *schema.Schema) {
+				name := "c"
+				s.Tables = append(s.Tables, &schema.Table{
+					Name:    "c",
+					Type:    "BASE TABLE",
+					Comment: "table c",
+					Columns: []*schema.Column{
+						{Name: "id", Type: "bigint"},
+						{Name: "a_id", Type: "integer", Nullable: true},
+					},
+					Indexes: []*schema.Index{
+						{Name: "c_pkey", Def: "PRIMARY KEY (id)", Table: &name, Columns: []string{"id"}},
+						{Name: "c_a_id_idx", Def: "CREATE INDEX c_a_id_idx ON c (a_id)", Table: &name, Columns: []string{"a_id"}},
+					},
+					Constraints: []*schema.Constraint{
+						{Name: "c_pkey", Type: "PRIMARY KEY", Def: "PRIMARY KEY (id)", Table: &name, Columns: []string{"id"}},
+						{Name: "c_a_id_fkey", Type: "FOREIGN KEY", Def: "FOREIGN KEY (a_id) REFERENCES a(a)", Table: &name, Columns: []string{"a_id"}, ReferencedTable: lo.ToPtr("a"), ReferencedColumns: []string{"a"}},
+					},
+				})
+			},
+			[]string{
+				"CREATE TABLE \"c\" (\n  \"id\" bigint NOT NULL,\n  \"a_id\" integer,\n  CONSTRAINT \"c_pkey\" PRIMARY KEY (\"id\")\n);",
+				`CREATE INDEX "c_a_id_idx" ON "c" ("a_id");`,
+				`ALTER TABLE "c" ADD CONSTRAINT "c_a_id_fkey" FOREIGN KEY ("a_id") REFERENCES "a" ("a");`,
+				`COMMENT ON TABLE "c" IS 'table c';`,
+			},
+		},
+		{
+			"drop constraint (mysql)",
+			MySQL,
+			func(s *schema.Schema) {
+				s.Tables[0].Constraints = nil
+			},
+			[]string{
+				"ALTER TABLE `a` DROP PRIMARY KEY;",
+			},
+		},
+		{
+			"drop index and trigger",
+			Postgres,
+			func(s *schema.Schema) {
+				s.Tables[0].Indexes = nil
+				s.Tables[0].Triggers = nil
+			},
+			[]string{
+				`DROP TRIGGER "update_a_a2" ON "a";`,
+			},
+		},
+		{
+			"modify enum",
+			Postgres,
+			func(s *schema.Schema) {
+				s.Enums[0].Values = []string{"one", "three", "four"}
+			},
+			[]string{
+				`ALTER TYPE "enum" ADD VALUE 'four';`,
+				"-- postgres does not support removing values (two) from enum enum; recreate the type",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := testutil.NewSchema(t)
+			to := testutil.NewSchema(t)
+			tt.modify(to)
+			got, err := Plan(from, to, tt.dialect)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestPlanFunction(t *testing.T) {
+	tests := []struct {
+		name string
+		from []*schema.Function
+		to   []*schema.Function
+		want []string
+	}{
+		{
+			"add function",
+			nil,
+			[]*schema.Function{{Name: "f", ReturnType: "integer", Arguments: "a integer", Type: "FUNCTION"}},
+			[]string{"-- definition of function f(a integer) is not available; create it manually"},
+		},
+		{
+			"drop function",
+			[]*schema.Function{{Name: "f", ReturnType: "integer", Arguments: "a integer", Type: "FUNCTION"}},
+			nil,
+			[]string{`DROP FUNCTION "f"(a integer);`},
+		},
+		{
+			"modify function",
+			[]*schema.Function{{Name: "f", ReturnType: "integer", Arguments: "a integer", Type: "FUNCTION"}},
+			[]*schema.Function{{Name: "f", ReturnType: "bigint", Arguments: "a integer", Type: "FUNCTION"}},
+			[]string{"-- definition of function f(a integer) is not available; alter it manually"},
+		},
+		{
+			"replace function",
+			[]*schema.Function{{Name: "f", ReturnType: "integer", Arguments: "a integer", Type: "FUNCTION"}},
+			[]*schema.Function{{Name: "f", ReturnType: "integer", Arguments: "a bigint", Type: "FUNCTION"}},
+			[]string{
+				`DROP FUNCTION "f"(a integer);`,
+				"-- definition of function f(a bigint) is not available; create it manually",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := testutil.NewSchema(t)
+			from.Functions = tt.from
+			to := testutil.NewSchema(t)
+			to.Functions = tt.to
+			got, err := Plan(from, to, Postgres)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestPlanWithDef(t *testing.T) {
+	from := testutil.NewSchema(t)
+	to := testutil.NewSchema(t)
+	to.Driver = &schema.Driver{Name: "sqlite"}
+	name := "c"
+	to.Tables = append(to.Tables, &schema.Table{
+		Name:    "c",
+		Type:    "table",
+		Def:     "CREATE TABLE c (\n  id INTEGER PRIMARY KEY\n)",
+		Columns: []*schema.Column{{Name: "id", Type: "INTEGER"}},
+		Indexes: []*schema.Index{
+			{Name: "c_id_idx", Def: "CREATE INDEX c_id_idx ON c (id)", Table: &name, Columns: []string{"id"}},
+		},
+	})
+	got, err := Plan(from, to, SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"CREATE TABLE c (\n  id INTEGER PRIMARY KEY\n);",
+		"CREATE INDEX c_id_idx ON c (id);",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestParseDialect(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Dialect
+		wantErr bool
+	}{
+		{"postgres", Postgres, false},
+		{"mariadb", MySQL, false},
+		{"sqlite3", SQLite, false},
+		{"oracle", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDialect(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package dbml
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/config"
+	"github.com/k1LoW/tbls/output"
+	"github.com/k1LoW/tbls/schema"
+)
+
+var _ output.Output = &Dbml{}
+
+var (
+	simpleIdentRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	simpleTypeRe  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\([0-9, ]*\))?(\[\])?$`)
+	numberRe      = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+	stringRe      = regexp.MustCompile(`^'(.*)'(::.+)?$`)
+	actionRe      = regexp.MustCompile(`(?i)ON (DELETE|UPDATE) (CASCADE|RESTRICT|SET NULL|SET DEFAULT|NO ACTION)`)
+)
+
+// Dbml struct
+type Dbml struct {
+	config *config.Config
+}
+
+// New return Dbml
+func New(c *config.Config) *Dbml {
+	return &Dbml{
+		config: c,
+	}
+}
+
+// OutputSchema output DBML format for full relation.
+func (d *Dbml) OutputSchema(wr io.Writer, s *schema.Schema) error {
+	b := new(strings.Builder)
+	d.writeProject(b, s)
+	for _, e := range s.Enums {
+		writeEnum(b, e)
+	}
+	for _, t := range s.Tables {
+		d.writeTable(b, t)
+	}
+	for _, r := range s.Relations {
+		writeRef(b, r)
+	}
+	writeTableGroups(b, s)
+	if _, err := io.WriteString(wr, strings.TrimSuffix(b.String(), "\n")+"\n"); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// OutputTable output DBML format for table.
+func (d *Dbml) OutputTable(wr io.Writer, t *schema.Table) error {
+	b := new(strings.Builder)
+	d.writeTable(b, t)
+	encountered := map[*schema.Relation]struct{}{}
+	for _, c := range t.Columns {
+		for _, rs := range [][]*schema.Relation{c.ParentRelations, c.ChildRelations} {
+			for _, r := range rs {
+				if _, ok := encountered[r]; ok {
+					continue
+				}
+				encountered[r] = struct{}{}
+				writeRef(b, r)
+			}
+		}
+	}
+	if _, err := io.WriteString(wr, strings.TrimSuffix(b.String(), "\n")+"\n"); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (d *Dbml) writeProject(b *strings.Builder, s *schema.Schema) {
+	fmt.Fprintf(b, "Project %s {\n", quoteIdent(s.Name))
+	if s.Driver != nil && s.Driver.Name != "" {
+		fmt.Fprintf(b, "  database_type: %s\n", quoteString(databaseType(s.Driver.Name)))
+	}
+	if s.Desc != "" {
+		fmt.Fprintf(b, "  Note: %s\n", quoteString(s.Desc))
+	}
+	b.WriteString("}\n\n")
+}
+
+func writeEnum(b *strings.Builder, e *schema.Enum) {
+	fmt.Fprintf(b, "Enum %s {\n", tableName(e.Name))
+	for _, v := range e.Values {
+		fmt.Fprintf(b, "  %s\n", quoteIdent(v))
+	}
+	b.WriteString("}\n\n")
+}
+
+func (d *Dbml) writeTable(b *strings.Builder, t *schema.Table) {
+	fmt.Fprintf(b, "Table %s {\n", tableName(t.Name))
+	pks := []*schema.Column{}
+	for _, c := range t.Columns {
+		if c.PK {
+			pks = append(pks, c)
+		}
+	}
+	for _, c := range t.Columns {
+		settings := []string{}
+		if c.PK && len(pks) == 1 {
+			settings = append(settings, "pk")
+		}
+		if strings.Contains(strings.ToLower(c.ExtraDef), "auto_increment") {
+			settings = append(settings, "increment")
+		}
+		if !c.Nullable {
+			settings = append(settings, "not null")
+		}
+		if c.Default.Valid {
+			settings = append(settings, fmt.Sprintf("default: %s", defaultValue(c.Default.String)))
+		}
+		if c.Comment != "" {
+			settings = append(settings, fmt.Sprintf("note: %s", quoteString(c.Comment)))
+		}
+		fmt.Fprintf(b, "  %s %s%s\n", quoteIdent(c.Name), columnType(c.Type), joinSettings(settings))
+	}
+
+	indexes := []string{}
+	if len(pks) > 1 {
+		cols := []string{}
+		for _, c := range pks {
+			cols = append(cols, quoteIdent(c.Name))
+		}
+		indexes = append(indexes, fmt.Sprintf("(%s) [pk]", strings.Join(cols, ", ")))
+	}
+	for _, i := range t.Indexes {
+		if len(i.Columns) == 0 || strings.Contains(strings.ToUpper(i.Def), "PRIMARY") {
+			continue
+		}
+		cols := []string{}
+		for _, c := range i.Columns {
+			if _, err := t.FindColumnByName(c); err != nil {
+				// expression
+				cols = append(cols, fmt.Sprintf("`%s`", c))
+				continue
+			}
+			cols = append(cols, quoteIdent(c))
+		}
+		settings := []string{fmt.Sprintf("name: %s", quoteString(i.Name))}
+		if strings.Contains(strings.ToUpper(i.Def), "UNIQUE") {
+			settings = append(settings, "unique")
+		}
+		if i.Comment != "" {
+			settings = append(settings, fmt.Sprintf("note: %s", quoteString(i.Comment)))
+		}
+		col := strings.Join(cols, ", ")
+		if len(cols) > 1 {
+			col = fmt.Sprintf("(%s)", col)
+		}
+		indexes = append(indexes, col+joinSettings(settings))
+	}
+	if len(indexes) > 0 {
+		b.WriteString("\n  Indexes {\n")
+		for _, i := range indexes {
+			fmt.Fprintf(b, "    %s\n", i)
+		}
+		b.WriteString("  }\n")
+	}
+	if t.Comment != "" {
+		fmt.Fprintf(b, "\n  Note: %s\n", quoteString(t.Comment))
+	}
+	b.WriteString("}\n\n")
+}
+
+func writeRef(b *strings.Builder, r *schema.Relation) {
+	fmt.Fprintf(b, "Ref: %s.%s %s %s.%s", tableName(r.Table.Name), columnNames(r.Columns), refOperator(r), tableName(r.ParentTable.Name), columnNames(r.ParentColumns))
+	settings := []string{}
+	for _, m := range actionRe.FindAllStringSubmatch(r.Def, -1) {
+		settings = append(settings, fmt.Sprintf("%s: %s", strings.ToLower(m[1]), strings.ToLower(m[2])))
+	}
+	b.WriteString(joinSettings(settings))
+	b.WriteString("\n\n")
+}
+
+func writeTableGroups(b *strings.Builder, s *schema.Schema) {
+	// A table can belong to only one TableGroup in DBML
+	grouped := map[string]struct{}{}
+	names := map[string]struct{}{}
+	for _, v := range s.Viewpoints {
+		if v.Schema == nil {
+			continue
+		}
+		for _, g := range v.Groups {
+			tables, _, err := v.Schema.SeparateTablesThatAreIncludedOrNot(&schema.FilterOption{
+				Include:       g.Tables,
+				IncludeLabels: g.Labels,
+			})
+			if err != nil {
+				continue
+			}
+			members := []string{}
+			for _, t := range tables {
+				if _, ok := grouped[t.Name]; ok {
+					continue
+				}
+				grouped[t.Name] = struct{}{}
+				members = append(members, tableName(t.Name))
+			}
+			if len(members) == 0 {
+				continue
+			}
+			name := g.Name
+			if _, ok := names[name]; ok {
+				name = fmt.Sprintf("%s %s", v.Name, g.Name)
+			}
+			names[name] = struct{}{}
+			fmt.Fprintf(b, "TableGroup %s {\n", quoteIdent(name))
+			for _, m := range members {
+				fmt.Fprintf(b, "  %s\n", m)
+			}
+			if g.Desc != "" {
+				fmt.Fprintf(b, "\n  Note: %s\n", quoteString(g.Desc))
+			}
+			b.WriteString("}\n\n")
+		}
+	}
+}
+
+// refOperator return the relationship operator from the cardinalities of child and parent
+func refOperator(r *schema.Relation) string {
+	childMany := r.Cardinality == schema.ZeroOrMore || r.Cardinality == schema.OneOrMore || r.Cardinality == schema.UnknownCardinality
+	parentMany := r.ParentCardinality == schema.ZeroOrMore || r.ParentCardinality == schema.OneOrMore
+	switch {
+	case childMany && parentMany:
+		return "<>"
+	case childMany:
+		return ">"
+	case parentMany:
+		return "<"
+	default:
+		return "-"
+	}
+}
+
+func columnNames(cs []*schema.Column) string {
+	names := []string{}
+	for _, c := range cs {
+		names = append(names, quoteIdent(c.Name))
+	}
+	if len(names) == 1 {
+		return names[0]
+	}
+	return fmt.Sprintf("(%s)", strings.Join(names, ", "))
+}
+
+// tableName return the table name. `schema.table` is treated as the table in the schema.
+func tableName(name string) string {
+	parts := strings.Split(name, ".")
+	if len(parts) != 2 {
+		return quoteIdent(name)
+	}
+	return fmt.Sprintf("%s.%s", quoteIdent(parts[0]), quoteIdent(parts[1]))
+}
+
+func quoteIdent(s string) string {
+	if simpleIdentRe.MatchString(s) {
+		return s
+	}
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(s, `"`, `\"`))
+}
+
+func quoteString(s string) string {
+	if strings.ContainsAny(s, "\r\n") {
+		return fmt.Sprintf("'''%s'''", strings.ReplaceAll(s, "'''", `\'''`))
+	}
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), "'", `\'`))
+}
+
+func columnType(t string) string {
+	if simpleTypeRe.MatchString(t) {
+		return t
+	}
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(t, `"`, `\"`))
+}
+
+func defaultValue(v string) string {
+	switch {
+	case numberRe.MatchString(v):
+		return v
+	case strings.EqualFold(v, "true"), strings.EqualFold(v, "false"), strings.EqualFold(v, "null"):
+		return strings.ToLower(v)
+	case stringRe.MatchString(v):
+		return quoteString(strings.ReplaceAll(stringRe.FindStringSubmatch(v)[1], "''", "'"))
+	default:
+		return fmt.Sprintf("`%s`", v)
+	}
+}
+
+func joinSettings(settings []string) string {
+	if len(settings) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" [%s]", strings.Join(settings, ", "))
+}
+
+func databaseType(driver string) string {
+	switch driver {
+	case "postgres":
+		return "PostgreSQL"
+	case "mysql", "mariadb":
+		return "MySQL"
+	case "sqlite":
+		return "SQLite"
+	case "sqlserver", "mssql":
+		return "SQL Server"
+	default:
+		return driver
+	}
+}
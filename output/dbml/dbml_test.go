@@ -0,0 +1,170 @@
+package dbml
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/tbls/config"
+	dbmlparser "github.com/k1LoW/tbls/dbml"
+	"github.com/k1LoW/tbls/schema"
+	"github.com/k1LoW/tbls/testutil"
+	"github.com/tenntenn/golden"
+)
+
+func TestOutputSchema(t *testing.T) {
+	s := testutil.NewSchema(t)
+	c, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.LoadConfigFile(filepath.Join(testdataDir(), "out_test_tbls.yml")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ModifySchema(s); err != nil {
+		t.Fatal(err)
+	}
+	o := New(c)
+	got := &bytes.Buffer{}
+	if err := o.OutputSchema(got, s); err != nil {
+		t.Fatal(err)
+	}
+	f := "dbml_test_schema"
+	if os.Getenv("UPDATE_GOLDEN") != "" {
+		golden.Update(t, testdataDir(), f, got)
+		return
+	}
+	if diff := golden.Diff(t, testdataDir(), f, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestOutputTable(t *testing.T) {
+	s := testutil.NewSchema(t)
+	c, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.LoadConfigFile(filepath.Join(testdataDir(), "out_test_tbls.yml")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ModifySchema(s); err != nil {
+		t.Fatal(err)
+	}
+	ta, err := s.FindTableByName("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := New(c)
+	got := &bytes.Buffer{}
+	if err := o.OutputTable(got, ta); err != nil {
+		t.Fatal(err)
+	}
+	f := "dbml_test_a"
+	if os.Getenv("UPDATE_GOLDEN") != "" {
+		golden.Update(t, testdataDir(), f, got)
+		return
+	}
+	if diff := golden.Diff(t, testdataDir(), f, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestRefOperator(t *testing.T) {
+	tests := []struct {
+		cardinality       schema.Cardinality
+		parentCardinality schema.Cardinality
+		want              string
+	}{
+		{schema.ZeroOrMore, schema.ExactlyOne, ">"},
+		{schema.UnknownCardinality, schema.UnknownCardinality, ">"},
+		{schema.ZeroOrOne, schema.ExactlyOne, "-"},
+		{schema.ExactlyOne, schema.OneOrMore, "<"},
+		{schema.OneOrMore, schema.ZeroOrMore, "<>"},
+	}
+	for _, tt := range tests {
+		r := &schema.Relation{Cardinality: tt.cardinality, ParentCardinality: tt.parentCardinality}
+		if got := refOperator(r); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestDefaultValue(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"1", "1"},
+		{"-1.5", "-1.5"},
+		{"TRUE", "true"},
+		{"'it''s'::character varying", `'it\'s'`},
+		{"now()", "`now()`"},
+	}
+	for _, tt := range tests {
+		if got := defaultValue(tt.in); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestTableGroupRoundTrip(t *testing.T) {
+	src := `
+Table users {
+  id integer [pk]
+}
+
+Table posts {
+  id integer [pk]
+  user_id integer [ref: > users.id]
+}
+
+Table logs {
+  id integer [pk]
+}
+
+TableGroup core {
+  users
+  posts
+
+  Note: 'Core tables'
+}
+`
+	s, err := dbmlparser.Parse(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	if err := New(c).OutputSchema(buf, s); err != nil {
+		t.Fatal(err)
+	}
+	s2, err := dbmlparser.Parse(buf.String())
+	if err != nil {
+		t.Fatalf("%s\n%s", err, buf.String())
+	}
+	type group struct {
+		Name   string
+		Desc   string
+		Tables []string
+	}
+	got := []group{}
+	for _, v := range s2.Viewpoints {
+		got = append(got, group{v.Name, v.Desc, v.Tables})
+	}
+	want := []group{{"core", "Core tables", []string{"users", "posts"}}}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("%s\n%s", diff, buf.String())
+	}
+}
+
+func testdataDir() string {
+	wd, _ := os.Getwd()
+	dir, _ := filepath.Abs(filepath.Join(filepath.Dir(filepath.Dir(wd)), "testdata"))
+	return dir
+}
@@ -6,14 +6,18 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/beta/freetype/truetype"
 	"github.com/goccy/go-graphviz"
 	"github.com/k1LoW/errors"
 	"github.com/k1LoW/ffff"
 	"github.com/k1LoW/tbls/config"
+	"github.com/k1LoW/tbls/output"
 	"github.com/k1LoW/tbls/output/dot"
 	"github.com/k1LoW/tbls/schema"
 	"golang.org/x/image/font"
@@ -25,13 +29,20 @@ import (
 type Gviz struct {
 	config *config.Config
 	dot    *dot.Dot
+	format string
 }
 
 // New return Gviz
 func New(c *config.Config) *Gviz {
+	return NewWithFormat(c, c.ER.Format)
+}
+
+// NewWithFormat return Gviz that renders images in the format instead of er.format of config
+func NewWithFormat(c *config.Config, format string) *Gviz {
 	return &Gviz{
 		config: c,
 		dot:    dot.New(c),
+		format: format,
 	}
 }
 
@@ -62,7 +73,41 @@ func (g *Gviz) OutputViewpoint(wr io.Writer, v *schema.Viewpoint) error {
 	return g.render(wr, buf.Bytes())
 }
 
-func (g *Gviz) render(wr io.Writer, b []byte) (e error) {
+// RenderDOT render DOT into the image in the process.
+func (g *Gviz) RenderDOT(wr io.Writer, b []byte) error {
+	return g.renderInProcess(wr, b)
+}
+
+func (g *Gviz) render(wr io.Writer, b []byte) error {
+	// go-graphviz can not render concurrently in a process, so parallel jobs render in subprocesses
+	if cmd := g.config.ERRenderCommand(); len(cmd) > 0 && g.config.Jobs() > 1 {
+		return g.renderInSubprocess(wr, b, cmd)
+	}
+	return g.renderInProcess(wr, b)
+}
+
+func (g *Gviz) renderInSubprocess(wr io.Writer, b []byte, cmd []string) error {
+	args := append(slices.Clone(cmd[1:]), "--format", g.format)
+	if g.config.ER.Font != "" {
+		args = append(args, "--font", g.config.ER.Font)
+	}
+	stderr := &bytes.Buffer{}
+	c := exec.Command(cmd[0], args...) // #nosec
+	c.Stdin = bytes.NewReader(b)
+	c.Stdout = wr
+	c.Stderr = stderr
+	if err := c.Run(); err != nil {
+		return errors.WithStack(fmt.Errorf("failed to render ER diagram: %w: %s", err, strings.TrimSpace(stderr.String())))
+	}
+	return nil
+}
+
+// renderMu serializes rendering because go-graphviz shares a single WebAssembly module ( and the font loader ) in the process.
+var renderMu sync.Mutex
+
+func (g *Gviz) renderInProcess(wr io.Writer, b []byte) (e error) {
+	renderMu.Lock()
+	defer renderMu.Unlock()
 	ctx := context.Background()
 	gviz, err := graphviz.New(ctx)
 	if err != nil {
@@ -90,7 +135,7 @@ func (g *Gviz) render(wr io.Writer, b []byte) (e error) {
 			e = errors.WithStack(err)
 		}
 	}()
-	if err := gviz.Render(ctx, graph, graphviz.Format(g.config.ER.Format), wr); err != nil {
+	if err := gviz.Render(ctx, graph, graphviz.Format(g.format), wr); err != nil {
 		return errors.WithStack(err)
 	}
 	return nil
@@ -105,8 +150,13 @@ func Output(s *schema.Schema, c *config.Config, force bool) (e error) {
 		return errors.WithStack(err)
 	}
 
-	if !force && outputErExists(s, c.ER.Format, fullPath) {
-		return errors.New("output ER diagram files already exists")
+	inc, err := output.NewIncremental(s, c, force)
+	if err != nil {
+		return err
+	}
+	// existing outputs are overwritten only with --force or by incremental generation from schema.hash.json
+	if !force && !inc.Enabled() && outputErExists(s, c.ER.Format, fullPath) {
+		return fmt.Errorf("output ER diagram files %w", output.ErrAlreadyExists)
 	}
 
 	err = os.MkdirAll(fullPath, 0755) // #nosec
@@ -114,46 +164,34 @@ func Output(s *schema.Schema, c *config.Config, force bool) (e error) {
 		return errors.WithStack(err)
 	}
 
-	fn := fmt.Sprintf("schema.%s", erFormat)
-	fmt.Printf("%s\n", filepath.Join(outputPath, fn))
-
-	f, err := os.OpenFile(filepath.Join(fullPath, fn), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
-	if err != nil {
-		return errors.WithStack(err)
-	}
 	g := New(c)
-	if err := g.OutputSchema(f, s); err != nil {
-		return errors.WithStack(err)
-	}
+	files := []*output.File{}
+
+	files = append(files, &output.File{
+		Name:      fmt.Sprintf("schema.%s", erFormat),
+		Unchanged: inc.SkipSchema,
+		Render:    func(wr io.Writer) error { return g.OutputSchema(wr, s) },
+	})
 
 	// tables
 	for _, t := range s.Tables {
-		fn := fmt.Sprintf("%s.%s", t.Name, erFormat)
-		fmt.Printf("%s\n", filepath.Join(outputPath, fn))
-
-		f, err := os.OpenFile(filepath.Join(fullPath, fn), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
-		if err != nil {
-			return errors.WithStack(err)
-		}
-		if err := g.OutputTable(f, t); err != nil {
-			return errors.WithStack(err)
-		}
+		files = append(files, &output.File{
+			Name:      fmt.Sprintf("%s.%s", t.Name, erFormat),
+			Unchanged: func(path string) bool { return inc.SkipTable(t, path) },
+			Render:    func(wr io.Writer) error { return g.OutputTable(wr, t) },
+		})
 	}
 
 	// viewpoints
 	for i, v := range s.Viewpoints {
-		fn := fmt.Sprintf("viewpoint-%d.%s", i, erFormat)
-		fmt.Printf("%s\n", filepath.Join(outputPath, fn))
-		f, err := os.OpenFile(filepath.Join(fullPath, fn), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
-		if err != nil {
-			return errors.WithStack(err)
-		}
-		if err := g.OutputViewpoint(f, v); err != nil {
-			return errors.WithStack(err)
-		}
+		files = append(files, &output.File{
+			Name:      fmt.Sprintf("viewpoint-%d.%s", i, erFormat),
+			Unchanged: inc.SkipSchema,
+			Render:    func(wr io.Writer) error { return g.OutputViewpoint(wr, v) },
+		})
 	}
 
-	return nil
+	return output.WriteFiles(fullPath, outputPath, files, c.Jobs())
 }
 
 // getFaceFunc
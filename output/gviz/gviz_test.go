@@ -93,6 +93,35 @@ func TestOutputTable(t *testing.T) {
 	}
 }
 
+func TestRenderInSubprocess(t *testing.T) {
+	tests := []struct {
+		jobs    int
+		cmd     []string
+		want    string
+		wantErr bool
+	}{
+		{2, []string{"sh", "-c", `printf '%s ' "$@"; cat`, "sh"}, "--format svg digraph {}", false},
+		{2, []string{"sh", "-c", "echo invalid DOT >&2; exit 1"}, "", true},
+	}
+	for _, tt := range tests {
+		c, err := config.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.LoadOption(config.ERFormat("svg"), config.Jobs(tt.jobs), config.ERRenderCommand(tt.cmd)); err != nil {
+			t.Fatal(err)
+		}
+		got := &bytes.Buffer{}
+		err = New(c).render(got, []byte("digraph {}"))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+		}
+		if got.String() != tt.want {
+			t.Errorf("got %q\nwant %q", got.String(), tt.want)
+		}
+	}
+}
+
 func testdataDir() string {
 	wd, _ := os.Getwd()
 	dir, _ := filepath.Abs(filepath.Join(filepath.Dir(filepath.Dir(wd)), "testdata"))
@@ -0,0 +1,48 @@
+(function () {
+  var index = window.tblsSearchIndex || [];
+  var tables = document.getElementById('tables');
+  var results = document.getElementById('search-results');
+  var input = document.getElementById('search');
+
+  function item(e) {
+    var li = document.createElement('li');
+    var a = document.createElement('a');
+    a.href = e.url;
+    a.textContent = e.column ? e.table + '.' + e.column : e.table;
+    li.appendChild(a);
+    if (e.comment) {
+      var span = document.createElement('span');
+      span.className = 'comment';
+      span.textContent = ' ' + e.comment;
+      li.appendChild(span);
+    }
+    return li;
+  }
+
+  index.forEach(function (e) {
+    if (!e.column) {
+      var li = document.createElement('li');
+      var a = document.createElement('a');
+      a.href = e.url;
+      a.textContent = e.table;
+      li.appendChild(a);
+      tables.appendChild(li);
+    }
+  });
+
+  input.addEventListener('input', function () {
+    var q = input.value.trim().toLowerCase();
+    results.innerHTML = '';
+    tables.style.display = q ? 'none' : '';
+    if (!q) {
+      return;
+    }
+    index.filter(function (e) {
+      return [e.table, e.column || '', e.comment || ''].some(function (v) {
+        return v.toLowerCase().indexOf(q) !== -1;
+      });
+    }).slice(0, 100).forEach(function (e) {
+      results.appendChild(item(e));
+    });
+  });
+})();
@@ -0,0 +1,339 @@
+package html
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/config"
+	"github.com/k1LoW/tbls/output"
+	"github.com/k1LoW/tbls/output/gviz"
+	"github.com/k1LoW/tbls/schema"
+	"github.com/samber/lo"
+)
+
+var _ output.Output = &Html{}
+
+//go:embed templates/* assets/*
+var fs embed.FS
+
+// assets are static files copied to the document directory
+var assets = []string{"style.css", "search.js"}
+
+const searchIndexFile = "search-index.js"
+
+// Html struct
+type Html struct {
+	config *config.Config
+	gviz   *gviz.Gviz
+}
+
+// New return Html
+func New(c *config.Config) *Html {
+	return &Html{
+		config: c,
+		// ER diagrams are always embedded in pages as inline SVG
+		gviz: gviz.NewWithFormat(c, "svg"),
+	}
+}
+
+// OutputSchema output index.html
+func (h *Html) OutputSchema(wr io.Writer, s *schema.Schema) error {
+	er, err := h.erDiagram(func(w io.Writer) error { return h.gviz.OutputSchema(w, s) })
+	if err != nil {
+		return err
+	}
+	return h.render(wr, "index", map[string]any{
+		"Title":     s.Name,
+		"Schema":    s,
+		"erDiagram": er,
+	})
+}
+
+// OutputTable output html for table
+func (h *Html) OutputTable(wr io.Writer, t *schema.Table) error {
+	er, err := h.erDiagram(func(w io.Writer) error { return h.gviz.OutputTable(w, t) })
+	if err != nil {
+		return err
+	}
+	return h.render(wr, "table", map[string]any{
+		"Title":           t.Name,
+		"Table":           t,
+		"ParentRelations": relationsOf(t, true),
+		"ChildRelations":  relationsOf(t, false),
+		"erDiagram":       er,
+	})
+}
+
+// OutputViewpoint output html for viewpoint
+func (h *Html) OutputViewpoint(wr io.Writer, i int, v *schema.Viewpoint) error {
+	er, err := h.erDiagram(func(w io.Writer) error { return h.gviz.OutputViewpoint(w, v) })
+	if err != nil {
+		return err
+	}
+	groups, err := viewpointGroups(v)
+	if err != nil {
+		return err
+	}
+	return h.render(wr, "viewpoint", map[string]any{
+		"Title":     v.Name,
+		"Viewpoint": v,
+		"Groups":    groups,
+		"erDiagram": er,
+	})
+}
+
+// Output generate html files
+func Output(s *schema.Schema, c *config.Config, force bool) (e error) {
+	docPath := c.DocPath
+	fullPath, err := filepath.Abs(docPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	inc, err := output.NewIncremental(s, c, force)
+	if err != nil {
+		return err
+	}
+	// existing outputs are overwritten only with --force or by incremental generation from schema.hash.json
+	if !force && !inc.Enabled() && outputExists(s, fullPath) {
+		return fmt.Errorf("output files %w", output.ErrAlreadyExists)
+	}
+	if err := os.MkdirAll(fullPath, 0755); err != nil { // #nosec
+		return errors.WithStack(err)
+	}
+	h := New(c)
+	files := []*output.File{}
+
+	// index.html
+	files = append(files, &output.File{
+		Name:      "index.html",
+		Unchanged: inc.SkipSchema,
+		Render:    func(wr io.Writer) error { return h.OutputSchema(wr, s) },
+	})
+
+	// tables
+	for _, t := range s.Tables {
+		files = append(files, &output.File{
+			Name:      fmt.Sprintf("%s.html", t.Name),
+			Unchanged: func(path string) bool { return inc.SkipTable(t, path) },
+			Render:    func(wr io.Writer) error { return h.OutputTable(wr, t) },
+		})
+	}
+
+	// viewpoints
+	for i, v := range s.Viewpoints {
+		files = append(files, &output.File{
+			Name:      fmt.Sprintf("viewpoint-%d.html", i),
+			Unchanged: inc.SkipSchema,
+			Render:    func(wr io.Writer) error { return h.OutputViewpoint(wr, i, v) },
+		})
+	}
+
+	// assets
+	for _, a := range assets {
+		files = append(files, &output.File{
+			Name:      a,
+			Unchanged: inc.SkipSchema,
+			Render: func(wr io.Writer) error {
+				b, err := Asset(a)
+				if err != nil {
+					return err
+				}
+				_, err = wr.Write(b)
+				return err
+			},
+		})
+	}
+	files = append(files, &output.File{
+		Name:      searchIndexFile,
+		Unchanged: inc.SkipSchema,
+		Render:    func(wr io.Writer) error { return OutputSearchIndex(wr, s) },
+	})
+	return output.WriteFiles(fullPath, docPath, files, c.Jobs())
+}
+
+// Asset return the static file of the document ( style.css, search.js )
+func Asset(name string) ([]byte, error) {
+	if !lo.Contains(assets, name) {
+		return nil, fmt.Errorf("not found asset '%s'", name)
+	}
+	b, err := fs.ReadFile("assets/" + name)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return b, nil
+}
+
+// searchEntry is the entry of the client-side search index
+type searchEntry struct {
+	Table   string `json:"table"`
+	Column  string `json:"column,omitempty"`
+	Comment string `json:"comment,omitempty"`
+	URL     string `json:"url"`
+}
+
+// OutputSearchIndex output the search index of tables and columns as JavaScript
+func OutputSearchIndex(wr io.Writer, s *schema.Schema) error {
+	entries := []searchEntry{}
+	for _, t := range s.Tables {
+		entries = append(entries, searchEntry{Table: t.Name, Comment: t.Comment, URL: tableHref(t.Name)})
+		for _, c := range t.Columns {
+			entries = append(entries, searchEntry{Table: t.Name, Column: c.Name, Comment: c.Comment, URL: tableHref(t.Name) + "#" + columnID(c.Name)})
+		}
+	}
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := fmt.Fprintf(wr, "window.tblsSearchIndex = %s;\n", b); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (h *Html) render(wr io.Writer, page string, data map[string]any) error {
+	tmpl, err := template.New("").Funcs(h.funcs()).ParseFS(fs, "templates/layout.html.tmpl", fmt.Sprintf("templates/%s.html.tmpl", page))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	data["er"] = !h.config.ER.Skip
+	data["showOnlyFirstParagraph"] = h.config.Format.ShowOnlyFirstParagraph
+	if err := tmpl.ExecuteTemplate(wr, "layout", data); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// erDiagram render the ER diagram as inline SVG
+func (h *Html) erDiagram(f func(w io.Writer) error) (template.HTML, error) {
+	if h.config.ER.Skip {
+		return "", nil
+	}
+	buf := new(bytes.Buffer)
+	if err := f(buf); err != nil {
+		return "", errors.WithStack(err)
+	}
+	svg := buf.String()
+	// remove XML declaration and DOCTYPE
+	if i := strings.Index(svg, "<svg"); i > 0 {
+		svg = svg[i:]
+	}
+	return template.HTML(svg), nil // #nosec
+}
+
+func (h *Html) funcs() template.FuncMap {
+	return template.FuncMap{
+		"lookup": func(text string) string {
+			return h.config.MergedDict.Lookup(text)
+		},
+		"nl2br": func(text string) template.HTML {
+			lines := strings.Split(strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\r", "\n"), "\n")
+			for i, l := range lines {
+				lines[i] = template.HTMLEscapeString(l)
+			}
+			return template.HTML(strings.Join(lines, "<br>")) // #nosec
+		},
+		"show_only_first_paragraph": output.ShowOnlyFirstParagraph,
+		"table_href":                tableHref,
+		"column_id":                 columnID,
+		"viewpoint_href": func(i int) string {
+			return fmt.Sprintf("viewpoint-%d.html", i)
+		},
+		"uniq_tables": func(rs []*schema.Relation, parent bool) []*schema.Table {
+			tables := []*schema.Table{}
+			for _, r := range rs {
+				if parent {
+					tables = append(tables, r.ParentTable)
+				} else {
+					tables = append(tables, r.Table)
+				}
+			}
+			return lo.Uniq(tables)
+		},
+	}
+}
+
+func tableHref(name string) string {
+	return url.PathEscape(name) + ".html"
+}
+
+func columnID(name string) string {
+	return "column-" + name
+}
+
+// relationsOf return the relations where the table is the child (parent = true) or the parent (parent = false)
+func relationsOf(t *schema.Table, parent bool) []*schema.Relation {
+	relations := []*schema.Relation{}
+	for _, c := range t.Columns {
+		rs := c.ChildRelations
+		if parent {
+			rs = c.ParentRelations
+		}
+		for _, r := range rs {
+			if !lo.Contains(relations, r) {
+				relations = append(relations, r)
+			}
+		}
+	}
+	return relations
+}
+
+func viewpointGroups(v *schema.Viewpoint) ([]map[string]any, error) {
+	groups := []map[string]any{}
+	nogroup := v.Schema.Tables
+	for _, g := range v.Groups {
+		tables, _, err := v.Schema.SeparateTablesThatAreIncludedOrNot(&schema.FilterOption{
+			Include:       g.Tables,
+			IncludeLabels: g.Labels,
+		})
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, map[string]any{
+			"Name":   g.Name,
+			"Desc":   g.Desc,
+			"Tables": tables,
+		})
+		nogroup = lo.Without(nogroup, tables...)
+	}
+	if len(nogroup) > 0 {
+		name := ""
+		if len(v.Groups) > 0 {
+			name = "-"
+		}
+		groups = append(groups, map[string]any{
+			"Name":   name,
+			"Desc":   "",
+			"Tables": nogroup,
+		})
+	}
+	return groups, nil
+}
+
+func outputExists(s *schema.Schema, path string) bool {
+	// index.html
+	if _, err := os.Lstat(filepath.Join(path, "index.html")); err == nil {
+		return true
+	}
+	// tables
+	for _, t := range s.Tables {
+		if _, err := os.Lstat(filepath.Join(path, fmt.Sprintf("%s.html", t.Name))); err == nil {
+			return true
+		}
+	}
+	// viewpoints
+	for i := range s.Viewpoints {
+		if _, err := os.Lstat(filepath.Join(path, fmt.Sprintf("viewpoint-%d.html", i))); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package html
+
+import (
+	"html/template"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k1LoW/tbls/config"
+	"github.com/k1LoW/tbls/testutil"
+	"github.com/tenntenn/golden"
+)
+
+func TestOutput(t *testing.T) {
+	tests := []struct {
+		gotFile  string
+		wantFile string
+	}{
+		{"index.html", "html_test_index.html"},
+		{"a.html", "html_test_a.html"},
+		{"view.html", "html_test_view.html"},
+		{"viewpoint-1.html", "html_test_viewpoint-1.html"},
+		{"search-index.js", "html_test_search-index.js"},
+	}
+	s := testutil.NewSchema(t)
+	c, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tempDir := t.TempDir()
+	if err := c.Load(filepath.Join(testdataDir(), "out_test_tbls.yml"), config.DocPath(tempDir), config.ERSkip(true)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ModifySchema(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := Output(s, c, true); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.gotFile, func(t *testing.T) {
+			got, err := os.ReadFile(filepath.Join(tempDir, tt.gotFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if os.Getenv("UPDATE_GOLDEN") != "" {
+				golden.Update(t, testdataDir(), tt.wantFile, got)
+				return
+			}
+			if diff := golden.Diff(t, testdataDir(), tt.wantFile, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	if err := Output(s, c, false); err == nil {
+		t.Error("want error when output files already exist")
+	}
+}
+
+func TestNl2br(t *testing.T) {
+	c, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := New(c)
+	got := h.funcs()["nl2br"].(func(string) template.HTML)("<b>a</b>\r\nb")
+	if want := "&lt;b&gt;a&lt;/b&gt;<br>b"; string(got) != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func testdataDir() string {
+	wd, _ := os.Getwd()
+	dir, _ := filepath.Abs(filepath.Join(filepath.Dir(filepath.Dir(wd)), "testdata"))
+	return dir
+}
@@ -0,0 +1,187 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/config"
+	"github.com/k1LoW/tbls/schema"
+	"github.com/k1LoW/tbls/version"
+)
+
+// Hashes is the content hashes of tables stored alongside schema.json for incremental generation
+type Hashes struct {
+	// Config is the hash of settings that affect all outputs ( config, template files and tbls version )
+	Config string `json:"config"`
+	// Schema is the hash of the schema except tables ( name, comment, functions, enums, driver and viewpoints )
+	Schema string            `json:"schema"`
+	Tables map[string]string `json:"tables"`
+}
+
+// Incremental decides which outputs can be skipped because their content has not changed since the last generation
+type Incremental struct {
+	prev *Hashes
+	cur  *Hashes
+}
+
+// NewIncremental return Incremental. Outputs are skipped only when incremental generation is requested ( config.Incremental ) and the previous hashes exist.
+// If force is true, nothing is skipped. The hashes are stored alongside schema.json, so incremental generation is disabled by disableOutputSchema.
+func NewIncremental(s *schema.Schema, c *config.Config, force bool) (*Incremental, error) {
+	cur, err := ComputeHashes(s, c)
+	if err != nil {
+		return nil, err
+	}
+	inc := &Incremental{cur: cur}
+	if force || !c.Incremental() || c.DisableOutputSchema {
+		return inc, nil
+	}
+	prev, err := ReadHashes(c.SchemaHashFilePath())
+	if err != nil {
+		return nil, err
+	}
+	inc.prev = prev
+	return inc, nil
+}
+
+// Enabled return true if the previous hashes are available
+func (inc *Incremental) Enabled() bool {
+	return inc.prev != nil
+}
+
+// SkipTable return true if the outputs of the table are unchanged and the file exists
+func (inc *Incremental) SkipTable(t *schema.Table, path string) bool {
+	if !inc.Enabled() || inc.prev.Config != inc.cur.Config {
+		return false
+	}
+	if inc.prev.Tables[t.Name] != inc.cur.Tables[t.Name] {
+		return false
+	}
+	return exists(path)
+}
+
+// SkipSchema return true if the schema and all tables are unchanged and the file exists. It is for outputs of the whole schema ( index, viewpoints ).
+func (inc *Incremental) SkipSchema(path string) bool {
+	if !inc.Enabled() || inc.prev.Config != inc.cur.Config || inc.prev.Schema != inc.cur.Schema || len(inc.prev.Tables) != len(inc.cur.Tables) {
+		return false
+	}
+	for n, h := range inc.cur.Tables {
+		if inc.prev.Tables[n] != h {
+			return false
+		}
+	}
+	return exists(path)
+}
+
+// ComputeHashes compute the content hashes of the schema and tables.
+// The hash of a table covers the table, its relations, the tables shown in its ER diagram and its viewpoints.
+func ComputeHashes(s *schema.Schema, c *config.Config) (*Hashes, error) {
+	ch, err := configHash(c)
+	if err != nil {
+		return nil, err
+	}
+	// tables and relations are covered by the hashes of tables
+	ss := *s
+	ss.Tables = nil
+	ss.Relations = nil
+	sb, err := json.Marshal(ss)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	h := &Hashes{Config: ch, Schema: hash(sb), Tables: map[string]string{}}
+	distance := config.DefaultERDistance
+	if c.ER.Distance != nil {
+		distance = *c.ER.Distance
+	}
+	for _, t := range s.Tables {
+		tables, relations, err := t.CollectTablesAndRelations(distance, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, cc := range t.Columns {
+			for _, r := range slices.Concat(cc.ParentRelations, cc.ChildRelations) {
+				if !slices.Contains(relations, r) {
+					relations = append(relations, r)
+				}
+			}
+		}
+		b, err := json.Marshal(struct {
+			Tables     []*schema.Table
+			Relations  []*schema.Relation
+			Viewpoints []*schema.TableViewpoint
+		}{tables, relations, t.Viewpoints})
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		h.Tables[t.Name] = hash(b)
+	}
+	return h, nil
+}
+
+// ReadHashes read Hashes from the file. It returns nil if the file does not exist.
+func ReadHashes(path string) (*Hashes, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+	h := &Hashes{}
+	if err := json.Unmarshal(b, h); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return h, nil
+}
+
+// WriteHashes compute and write the content hashes of tables alongside schema.json
+func WriteHashes(s *schema.Schema, c *config.Config) error {
+	h, err := ComputeHashes(s, c)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := WriteFileIfChanged(c.SchemaHashFilePath(), b); err != nil {
+		return err
+	}
+	return nil
+}
+
+func configHash(c *config.Config) (string, error) {
+	b, err := json.Marshal(struct {
+		Version string
+		Config  *config.Config
+	}{version.Version, c})
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	for _, f := range c.Templates.Files() {
+		tb, err := os.ReadFile(filepath.Clean(f))
+		if err != nil {
+			// templates not used by the output may not exist
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", errors.WithStack(err)
+		}
+		b = append(b, tb...)
+	}
+	return hash(b), nil
+}
+
+func hash(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
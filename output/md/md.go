@@ -137,8 +137,13 @@ func Output(s *schema.Schema, c *config.Config, force bool) (e error) {
 		return errors.WithStack(err)
 	}
 
-	if !force && outputExists(s, fullPath) {
-		return errors.New("output files already exists")
+	inc, err := output.NewIncremental(s, c, force)
+	if err != nil {
+		return err
+	}
+	// existing outputs are overwritten only with --force or by incremental generation from schema.hash.json
+	if !force && !inc.Enabled() && outputExists(s, fullPath) {
+		return fmt.Errorf("output files %w", output.ErrAlreadyExists)
 	}
 
 	err = os.MkdirAll(fullPath, 0755) // #nosec
@@ -146,59 +151,35 @@ func Output(s *schema.Schema, c *config.Config, force bool) (e error) {
 		return errors.WithStack(err)
 	}
 
-	// README.md
-	f, err := os.Create(filepath.Clean(filepath.Join(fullPath, "README.md")))
-	defer func() {
-		err := f.Close()
-		if err != nil {
-			e = err
-		}
-	}()
-	if err != nil {
-		return errors.WithStack(err)
-	}
 	md := New(c)
-	if err := md.OutputSchema(f, s); err != nil {
-		return errors.WithStack(err)
-	}
-	fmt.Printf("%s\n", filepath.Join(docPath, "README.md"))
+	files := []*output.File{}
+
+	// README.md
+	files = append(files, &output.File{
+		Name:      "README.md",
+		Unchanged: inc.SkipSchema,
+		Render:    func(wr io.Writer) error { return md.OutputSchema(wr, s) },
+	})
 
 	// tables
 	for _, t := range s.Tables {
-		f, err := os.Create(filepath.Clean(filepath.Join(fullPath, fmt.Sprintf("%s.md", t.Name))))
-		if err != nil {
-			_ = f.Close()
-			return errors.WithStack(err)
-		}
-		if err := md.OutputTable(f, t); err != nil {
-			_ = f.Close()
-			return errors.WithStack(err)
-		}
-		fmt.Printf("%s\n", filepath.Join(docPath, fmt.Sprintf("%s.md", t.Name)))
-		if err := f.Close(); err != nil {
-			return errors.WithStack(err)
-		}
+		files = append(files, &output.File{
+			Name:      fmt.Sprintf("%s.md", t.Name),
+			Unchanged: func(path string) bool { return inc.SkipTable(t, path) },
+			Render:    func(wr io.Writer) error { return md.OutputTable(wr, t) },
+		})
 	}
 
 	// viewpoints
 	for i, v := range s.Viewpoints {
-		fn := fmt.Sprintf("viewpoint-%d.md", i)
-		f, err := os.Create(filepath.Clean(filepath.Join(fullPath, fn)))
-		if err != nil {
-			_ = f.Close()
-			return errors.WithStack(err)
-		}
-		if err := md.OutputViewpoint(f, i, v); err != nil {
-			_ = f.Close()
-			return errors.WithStack(err)
-		}
-		fmt.Printf("%s\n", filepath.Join(docPath, fn))
-		if err := f.Close(); err != nil {
-			return errors.WithStack(err)
-		}
+		files = append(files, &output.File{
+			Name:      fmt.Sprintf("viewpoint-%d.md", i),
+			Unchanged: inc.SkipSchema,
+			Render:    func(wr io.Writer) error { return md.OutputViewpoint(wr, i, v) },
+		})
 	}
 
-	return nil
+	return output.WriteFiles(fullPath, docPath, files, c.Jobs())
 }
 
 // DiffSchemas show diff databases.
@@ -4,9 +4,12 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 	"testing"
 
+	"github.com/k1LoW/errors"
 	"github.com/k1LoW/tbls/config"
+	"github.com/k1LoW/tbls/output"
 	"github.com/k1LoW/tbls/schema"
 	"github.com/k1LoW/tbls/testutil"
 	"github.com/tenntenn/golden"
@@ -81,6 +84,8 @@ func TestOutput(t *testing.T) {
 				config.Adjust(tt.adjust),
 				config.ERFormat(erFormat),
 				config.ERSkip(tt.skipER),
+				// pages are rendered in parallel, but the output must be the same as golden files
+				config.Jobs(4),
 			}
 			if err := c.Load(filepath.Join(testdataDir(), "out_test_tbls.yml"), opts...); err != nil {
 				t.Error(err)
@@ -154,6 +159,110 @@ func TestOutputTemplate(t *testing.T) {
 	}
 }
 
+func TestOutputAlreadyExists(t *testing.T) {
+	tests := []struct {
+		name        string
+		force       bool
+		incremental bool
+		withHashes  bool
+		wantErr     bool
+	}{
+		{"overwrite is not allowed", false, false, true, true},
+		{"--force", true, false, true, false},
+		{"--incremental", false, true, true, false},
+		{"--incremental without schema.hash.json", false, true, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := testutil.NewSchema(t)
+			c, err := config.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := c.Load(filepath.Join(testdataDir(), "out_test_tbls.yml"), config.DocPath(t.TempDir()), config.ERSkip(true)); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.ModifySchema(s); err != nil {
+				t.Fatal(err)
+			}
+			if err := Output(s, c, false); err != nil {
+				t.Fatal(err)
+			}
+			if tt.withHashes {
+				if err := output.WriteHashes(s, c); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := config.Incremental(tt.incremental)(c); err != nil {
+				t.Fatal(err)
+			}
+			err = Output(s, c, tt.force)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, output.ErrAlreadyExists) {
+				t.Errorf("got %v\nwant %v", err, output.ErrAlreadyExists)
+			}
+		})
+	}
+}
+
+func TestOutputIncremental(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *schema.Schema)
+		want   string
+	}{
+		{
+			"function added",
+			func(s *schema.Schema) {
+				s.Functions = append(s.Functions, &schema.Function{Name: "update_updated", ReturnType: "trigger", Type: "FUNCTION"})
+			},
+			"update_updated",
+		},
+		{
+			"enum added",
+			func(s *schema.Schema) {
+				s.Enums = append(s.Enums, &schema.Enum{Name: "post_status", Values: []string{"draft", "published"}})
+			},
+			"post_status",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := testutil.NewSchema(t)
+			c, err := config.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			docPath := t.TempDir()
+			if err := c.Load(filepath.Join(testdataDir(), "out_test_tbls.yml"), config.DocPath(docPath), config.ERSkip(true), config.Incremental(true)); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.ModifySchema(s); err != nil {
+				t.Fatal(err)
+			}
+			if err := Output(s, c, false); err != nil {
+				t.Fatal(err)
+			}
+			if err := output.WriteHashes(s, c); err != nil {
+				t.Fatal(err)
+			}
+			tt.modify(s)
+			if err := Output(s, c, false); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(filepath.Join(docPath, "README.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(got), tt.want) {
+				t.Errorf("README.md is not regenerated: %s is not found", tt.want)
+			}
+		})
+	}
+}
+
 func TestDiffSchemaAndDocs(t *testing.T) {
 	for _, tt := range tests {
 		func() {
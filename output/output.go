@@ -1,12 +1,16 @@
 package output
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"text/template"
 
+	"github.com/k1LoW/errors"
 	"github.com/k1LoW/tbls/dict"
 	"github.com/k1LoW/tbls/schema"
 	"gitlab.com/golang-commonmark/mdurl"
@@ -18,6 +22,9 @@ type Output interface {
 	OutputTable(wr io.Writer, s *schema.Table) error
 }
 
+// ErrAlreadyExists is the error returned when the output files already exist and overwriting is not allowed
+var ErrAlreadyExists = errors.New("already exists")
+
 var escapeMermaidRe = regexp.MustCompile(`[^a-zA-Z0-9_\-]`)
 
 func Funcs(d *dict.Dict) map[string]interface{} {
@@ -110,3 +117,15 @@ func LabelJoin(labels schema.Labels) string {
 	}
 	return fmt.Sprintf("`%s`", strings.Join(m, "` `"))
 }
+
+// WriteFileIfChanged write b to the file only when the content differs from the existing file. It returns true if the file is written.
+func WriteFileIfChanged(path string, b []byte) (bool, error) {
+	current, err := os.ReadFile(filepath.Clean(path))
+	if err == nil && bytes.Equal(current, b) {
+		return false, nil
+	}
+	if err := os.WriteFile(filepath.Clean(path), b, 0644); err != nil { // #nosec
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
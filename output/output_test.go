@@ -1,8 +1,15 @@
 package output
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/k1LoW/errors"
 	"github.com/k1LoW/tbls/config"
 	"github.com/k1LoW/tbls/datasource"
 	"github.com/k1LoW/tbls/schema"
@@ -51,3 +58,229 @@ func TestDistance(t *testing.T) {
 		t.Errorf("got %v\nwant %v", len(relations), want)
 	}
 }
+
+func TestWriteFileIfChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "README.md")
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"# a", true},
+		{"# a", false},
+		{"# b", true},
+	}
+	for _, tt := range tests {
+		got, err := WriteFileIfChanged(path, []byte(tt.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %v want %v", tt.in, got, tt.want)
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.in {
+			t.Errorf("got %s want %s", b, tt.in)
+		}
+	}
+}
+
+func TestIncremental(t *testing.T) {
+	tests := []struct {
+		name       string
+		modify     func(s *schema.Schema, c *config.Config)
+		want       map[string]bool
+		wantSchema bool
+	}{
+		{
+			"no changes",
+			func(s *schema.Schema, c *config.Config) {},
+			map[string]bool{"public.users": true, "public.CamelizeTable": true, "public.hyphen-table": true, "backup.blogs": true},
+			true,
+		},
+		{
+			"table comment changed",
+			func(s *schema.Schema, c *config.Config) {
+				ct, _ := s.FindTableByName("public.CamelizeTable")
+				ct.Comment = "changed"
+			},
+			map[string]bool{"public.users": true, "public.CamelizeTable": false, "public.hyphen-table": false, "backup.blogs": true},
+			false,
+		},
+		{
+			"column comment changed",
+			func(s *schema.Schema, c *config.Config) {
+				ct, _ := s.FindTableByName("backup.blogs")
+				ct.Columns[0].Comment = "changed"
+			},
+			map[string]bool{"public.users": true, "public.CamelizeTable": true, "public.hyphen-table": true, "backup.blogs": false},
+			false,
+		},
+		{
+			"function added",
+			func(s *schema.Schema, c *config.Config) {
+				s.Functions = append(s.Functions, &schema.Function{Name: "public.update_updated", ReturnType: "trigger", Type: "FUNCTION"})
+			},
+			map[string]bool{"public.users": true, "public.CamelizeTable": true, "public.hyphen-table": true, "backup.blogs": true},
+			false,
+		},
+		{
+			"enum changed",
+			func(s *schema.Schema, c *config.Config) {
+				s.Enums = append(s.Enums, &schema.Enum{Name: "public.status", Values: []string{"draft", "published"}})
+			},
+			map[string]bool{"public.users": true, "public.CamelizeTable": true, "public.hyphen-table": true, "backup.blogs": true},
+			false,
+		},
+		{
+			"config changed",
+			func(s *schema.Schema, c *config.Config) {
+				c.Format.Adjust = true
+			},
+			map[string]bool{"public.users": false, "public.CamelizeTable": false, "public.hyphen-table": false, "backup.blogs": false},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docPath := t.TempDir()
+			s, c := newIncrementalTestSchema(t, docPath)
+			if err := WriteHashes(s, c); err != nil {
+				t.Fatal(err)
+			}
+			s, c = newIncrementalTestSchema(t, docPath)
+			tt.modify(s, c)
+			inc, err := NewIncremental(s, c, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !inc.Enabled() {
+				t.Fatal("incremental generation should be enabled")
+			}
+			for name, want := range tt.want {
+				tbl, err := s.FindTableByName(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				path := filepath.Join(docPath, tbl.Name+".md")
+				if got := inc.SkipTable(tbl, path); got {
+					t.Errorf("%s: the file does not exist, but got skipped", name)
+				}
+				if err := os.WriteFile(path, []byte{}, 0600); err != nil {
+					t.Fatal(err)
+				}
+				if got := inc.SkipTable(tbl, path); got != want {
+					t.Errorf("%s: got %v\nwant %v", name, got, want)
+				}
+			}
+			path := filepath.Join(docPath, "README.md")
+			if err := os.WriteFile(path, []byte{}, 0600); err != nil {
+				t.Fatal(err)
+			}
+			if got := inc.SkipSchema(path); got != tt.wantSchema {
+				t.Errorf("README.md: got %v\nwant %v", got, tt.wantSchema)
+			}
+
+			force, err := NewIncremental(s, c, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if force.Enabled() || force.SkipSchema(path) {
+				t.Error("force should rebuild everything")
+			}
+
+			if err := config.Incremental(false)(c); err != nil {
+				t.Fatal(err)
+			}
+			notRequested, err := NewIncremental(s, c, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if notRequested.Enabled() || notRequested.SkipSchema(path) {
+				t.Error("incremental generation should be enabled only when it is requested")
+			}
+		})
+	}
+}
+
+func newIncrementalTestSchema(t *testing.T, docPath string) (*schema.Schema, *config.Config) {
+	t.Helper()
+	s, err := datasource.Analyze(config.DSN{URL: "json://../testdata/testdb.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.DocPath = docPath
+	if err := config.Incremental(true)(c); err != nil {
+		t.Fatal(err)
+	}
+	return s, c
+}
+
+func TestWriteFiles(t *testing.T) {
+	errRender := errors.New("render error")
+	tests := []struct {
+		name    string
+		jobs    int
+		failed  []int
+		wantErr bool
+	}{
+		{"sequential", 1, nil, false},
+		{"parallel", 4, nil, false},
+		{"more jobs than files", 100, nil, false},
+		{"errors are joined", 4, []int{3, 7}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			files := []*File{}
+			for i := range 10 {
+				files = append(files, &File{
+					Name:      fmt.Sprintf("%d.md", i),
+					Unchanged: func(path string) bool { return i == 0 },
+					Render: func(wr io.Writer) error {
+						if slices.Contains(tt.failed, i) {
+							return errRender
+						}
+						_, err := fmt.Fprintf(wr, "file %d", i)
+						return err
+					},
+				})
+			}
+			err := WriteFiles(dir, dir, files, tt.jobs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, errRender) {
+					t.Errorf("got %v\nwant %v", err, errRender)
+				}
+				for _, i := range tt.failed {
+					if !strings.Contains(err.Error(), fmt.Sprintf("%d.md", i)) {
+						t.Errorf("the error should contain %d.md: %v", i, err)
+					}
+				}
+			}
+			for i, f := range files {
+				b, err := os.ReadFile(filepath.Join(dir, f.Name))
+				if i == 0 || slices.Contains(tt.failed, i) {
+					if err == nil {
+						t.Errorf("%s should not be written", f.Name)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, want := string(b), fmt.Sprintf("file %d", i); got != want {
+					t.Errorf("got %v\nwant %v", got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/k1LoW/errors"
+)
+
+// File is the output file written by WriteFiles
+type File struct {
+	Name string
+	// Unchanged return true if the file can be skipped by incremental generation
+	Unchanged func(path string) bool
+	Render    func(wr io.Writer) error
+}
+
+type rendered struct {
+	b         []byte
+	unchanged bool
+	err       error
+}
+
+// WriteFiles render files in parallel with at most jobs goroutines and write them into dir in the order of files.
+// The paths of written files are printed as filepath.Join(printDir, name), so the output is deterministic.
+// Files that fail to render do not stop the others, and all the errors are joined.
+func WriteFiles(dir, printDir string, files []*File, jobs int) error {
+	results := make([]chan rendered, len(files))
+	for i := range files {
+		results[i] = make(chan rendered, 1)
+	}
+	go func() {
+		sem := make(chan struct{}, max(jobs, 1))
+		for i, f := range files {
+			sem <- struct{}{}
+			go func() {
+				defer func() { <-sem }()
+				if f.Unchanged != nil && f.Unchanged(filepath.Join(dir, f.Name)) {
+					results[i] <- rendered{unchanged: true}
+					return
+				}
+				buf := new(bytes.Buffer)
+				if err := f.Render(buf); err != nil {
+					results[i] <- rendered{err: err}
+					return
+				}
+				results[i] <- rendered{b: buf.Bytes()}
+			}()
+		}
+	}()
+
+	errs := []error{}
+	for i, f := range files {
+		r := <-results[i]
+		if r.err != nil {
+			errs = append(errs, errors.WithStack(fmt.Errorf("failed to output %s: %w", f.Name, r.err)))
+			continue
+		}
+		if r.unchanged {
+			continue
+		}
+		written, err := WriteFileIfChanged(filepath.Join(dir, f.Name), r.b)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if written {
+			fmt.Printf("%s\n", filepath.Join(printDir, f.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,429 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/k1LoW/tbls/schema"
+	"github.com/samber/lo"
+)
+
+// Severity is the compatibility impact of a change
+type Severity string
+
+const (
+	// SeverityNone is a change that does not affect compatibility (comments, labels, viewpoints, etc.)
+	SeverityNone Severity = "none"
+	// SeverityAdditive is a backward compatible change
+	SeverityAdditive Severity = "additive"
+	// SeverityBreaking is a backward incompatible change
+	SeverityBreaking Severity = "breaking"
+)
+
+var severityLevels = map[Severity]int{
+	SeverityNone:     0,
+	SeverityAdditive: 1,
+	SeverityBreaking: 2,
+}
+
+// ParseSeverity parse severity string
+func ParseSeverity(str string) (Severity, error) {
+	s := Severity(strings.ToLower(str))
+	if _, ok := severityLevels[s]; !ok {
+		return "", fmt.Errorf("invalid severity '%s'. valid values are breaking, additive and none", str)
+	}
+	return s, nil
+}
+
+// AtLeast return whether the severity is equal to or higher than s2
+func (s Severity) AtLeast(s2 Severity) bool {
+	return severityLevels[s] >= severityLevels[s2]
+}
+
+// MaxSeverity return the highest severity of the changes
+func (d *Diff) MaxSeverity() Severity {
+	m := SeverityNone
+	for _, c := range d.Changes {
+		if c.Severity.AtLeast(m) {
+			m = c.Severity
+		}
+	}
+	return m
+}
+
+// HasChangesAtLeast return whether the diff has any change with the severity equal to or higher than s
+func (d *Diff) HasChangesAtLeast(s Severity) bool {
+	return lo.ContainsBy(d.Changes, func(c *Change) bool { return c.Severity.AtLeast(s) })
+}
+
+// Classify tags each change with the severity and the reason
+func (d *Diff) Classify() {
+	for _, c := range d.Changes {
+		c.Severity, c.Reason = d.classify(c)
+	}
+}
+
+func (d *Diff) classify(c *Change) (Severity, string) {
+	switch c.Kind {
+	case KindTable:
+		return d.classifyTable(c)
+	case KindColumn:
+		return d.classifyColumn(c)
+	case KindIndex:
+		return d.classifyIndex(c)
+	case KindConstraint:
+		return d.classifyConstraint(c)
+	case KindTrigger:
+		if c.Op == OpModified && !hasFieldsExcept(c, "comment") {
+			return SeverityNone, ""
+		}
+		return SeverityAdditive, fmt.Sprintf("trigger %s", c.Op)
+	case KindRelation:
+		return SeverityNone, ""
+	case KindFunction:
+		switch c.Op {
+		case OpRemoved:
+			return SeverityBreaking, "function dropped"
+		case OpAdded:
+			return SeverityAdditive, "function added"
+		default:
+			return SeverityBreaking, "function signature changed"
+		}
+	case KindEnum:
+		switch c.Op {
+		case OpRemoved:
+			return SeverityBreaking, "enum dropped"
+		case OpAdded:
+			return SeverityAdditive, "enum added"
+		default:
+			f := findField(c, "values")
+			removed := lo.Without(splitList(f.From), splitList(f.To)...)
+			if len(removed) > 0 {
+				return SeverityBreaking, fmt.Sprintf("enum values removed: %s", strings.Join(removed, ", "))
+			}
+			return SeverityAdditive, "enum values added"
+		}
+	}
+	return SeverityNone, ""
+}
+
+func (d *Diff) classifyTable(c *Change) (Severity, string) {
+	switch c.Op {
+	case OpRemoved:
+		views := d.viewsReferencing(c.Name)
+		if len(views) > 0 {
+			return SeverityBreaking, fmt.Sprintf("table dropped; referenced by %s", strings.Join(views, ", "))
+		}
+		return SeverityBreaking, "table dropped"
+	case OpAdded:
+		return SeverityAdditive, "table added"
+	}
+	if f := findField(c, "type"); f != nil {
+		return SeverityBreaking, fmt.Sprintf("table type changed from %s to %s", f.From, f.To)
+	}
+	if f := findField(c, "def"); f != nil && d.isView(c.Name) {
+		return SeverityAdditive, "view definition changed"
+	}
+	return SeverityNone, ""
+}
+
+func (d *Diff) classifyColumn(c *Change) (Severity, string) {
+	switch c.Op {
+	case OpRemoved:
+		return SeverityBreaking, "column dropped"
+	case OpAdded:
+		col := d.findColumn(d.to, c.Table, c.Name)
+		if col != nil && !col.Nullable && !col.Default.Valid {
+			return SeverityBreaking, "NOT NULL column added without default"
+		}
+		return SeverityAdditive, "column added"
+	}
+	severity := SeverityNone
+	reasons := []string{}
+	add := func(s Severity, reason string) {
+		if s.AtLeast(severity) {
+			severity = s
+		}
+		reasons = append(reasons, reason)
+	}
+	if f := findField(c, "type"); f != nil {
+		switch compareTypes(f.From, f.To) {
+		case typeWidened:
+			add(SeverityAdditive, fmt.Sprintf("type widened from %s to %s", f.From, f.To))
+		default:
+			add(SeverityBreaking, fmt.Sprintf("type narrowed or changed from %s to %s", f.From, f.To))
+		}
+	}
+	col := d.findColumn(d.to, c.Table, c.Name)
+	if f := findField(c, "nullable"); f != nil {
+		if f.To == "false" && (col == nil || !col.Default.Valid) {
+			add(SeverityBreaking, "NOT NULL added without default")
+		} else {
+			add(SeverityAdditive, "nullability changed")
+		}
+	}
+	if f := findField(c, "default"); f != nil {
+		if f.To == "" && col != nil && !col.Nullable {
+			add(SeverityBreaking, "default dropped from NOT NULL column")
+		} else {
+			add(SeverityAdditive, "default changed")
+		}
+	}
+	if f := findField(c, "extra_def"); f != nil {
+		add(SeverityAdditive, "extra definition changed")
+	}
+	return severity, strings.Join(reasons, "; ")
+}
+
+func (d *Diff) classifyIndex(c *Change) (Severity, string) {
+	switch c.Op {
+	case OpRemoved:
+		if i := d.findIndex(d.from, c.Table, c.Name); i != nil && isUniqueIndex(i) {
+			return SeverityBreaking, "unique index dropped"
+		}
+		return SeverityAdditive, "index dropped"
+	case OpAdded:
+		if i := d.findIndex(d.to, c.Table, c.Name); i != nil && isUniqueIndex(i) {
+			return SeverityBreaking, "unique index added"
+		}
+		return SeverityAdditive, "index added"
+	}
+	if !hasFieldsExcept(c, "comment") {
+		return SeverityNone, ""
+	}
+	fi := d.findIndex(d.from, c.Table, c.Name)
+	ti := d.findIndex(d.to, c.Table, c.Name)
+	if (fi != nil && isUniqueIndex(fi)) || (ti != nil && isUniqueIndex(ti)) {
+		return SeverityBreaking, "unique index changed"
+	}
+	return SeverityAdditive, "index changed"
+}
+
+func (d *Diff) classifyConstraint(c *Change) (Severity, string) {
+	switch c.Op {
+	case OpRemoved:
+		if cs := d.findConstraint(d.from, c.Table, c.Name); cs != nil && isUniqueConstraint(cs) {
+			return SeverityBreaking, "unique or primary key constraint dropped"
+		}
+		return SeverityAdditive, "constraint dropped"
+	case OpAdded:
+		return SeverityBreaking, "constraint added"
+	}
+	if !hasFieldsExcept(c, "comment") {
+		return SeverityNone, ""
+	}
+	return SeverityBreaking, "constraint changed"
+}
+
+func (d *Diff) viewsReferencing(name string) []string {
+	if d.from == nil {
+		return nil
+	}
+	views := []string{}
+	for _, t := range d.from.Tables {
+		if lo.ContainsBy(t.ReferencedTables, func(rt *schema.Table) bool { return rt.Name == name }) {
+			views = append(views, t.Name)
+		}
+	}
+	return views
+}
+
+func (d *Diff) isView(name string) bool {
+	if d.to == nil {
+		return false
+	}
+	t, err := d.to.FindTableByName(name)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToUpper(t.Type), "VIEW")
+}
+
+func (d *Diff) findColumn(s *schema.Schema, table, name string) *schema.Column {
+	if s == nil {
+		return nil
+	}
+	t, err := s.FindTableByName(table)
+	if err != nil {
+		return nil
+	}
+	c, err := t.FindColumnByName(name)
+	if err != nil {
+		return nil
+	}
+	return c
+}
+
+func (d *Diff) findIndex(s *schema.Schema, table, name string) *schema.Index {
+	if s == nil {
+		return nil
+	}
+	t, err := s.FindTableByName(table)
+	if err != nil {
+		return nil
+	}
+	i, _ := lo.Find(t.Indexes, func(i *schema.Index) bool { return i.Name == name })
+	return i
+}
+
+func (d *Diff) findConstraint(s *schema.Schema, table, name string) *schema.Constraint {
+	if s == nil {
+		return nil
+	}
+	t, err := s.FindTableByName(table)
+	if err != nil {
+		return nil
+	}
+	c, _ := lo.Find(t.Constraints, func(c *schema.Constraint) bool { return c.Name == name })
+	return c
+}
+
+func isUniqueIndex(i *schema.Index) bool {
+	def := strings.ToUpper(i.Def)
+	return strings.Contains(def, "UNIQUE") || strings.Contains(def, "PRIMARY")
+}
+
+func isUniqueConstraint(c *schema.Constraint) bool {
+	t := strings.ToUpper(c.Type + " " + c.Def)
+	return strings.Contains(t, "UNIQUE") || strings.Contains(t, "PRIMARY")
+}
+
+func findField(c *Change, name string) *Field {
+	f, _ := lo.Find(c.Fields, func(f *Field) bool { return f.Name == name })
+	return f
+}
+
+func hasFieldsExcept(c *Change, names ...string) bool {
+	return lo.ContainsBy(c.Fields, func(f *Field) bool { return !lo.Contains(names, f.Name) })
+}
+
+func splitList(str string) []string {
+	if str == "" {
+		return nil
+	}
+	return strings.Split(str, ", ")
+}
+
+type typeChange int
+
+const (
+	typeIncompatible typeChange = iota
+	typeWidened
+	typeNarrowed
+)
+
+var typeRe = regexp.MustCompile(`^\s*([a-zA-Z0-9_ ]+?)\s*(?:\(([0-9,\s]+)\))?\s*(unsigned)?\s*$`)
+
+// typeFamilies is the list of type families ordered from narrow to wide
+var typeFamilies = [][][]string{
+	{
+		{"tinyint", "int1"},
+		{"smallint", "int2", "smallserial"},
+		{"mediumint", "int3"},
+		{"int", "integer", "int4", "serial"},
+		{"bigint", "int8", "bigserial"},
+	},
+	{
+		{"real", "float4", "float"},
+		{"double", "double precision", "float8"},
+	},
+	{
+		{"char", "character", "bpchar", "nchar"},
+		{"varchar", "character varying", "nvarchar", "varchar2"},
+		{"tinytext"},
+		{"text", "mediumtext", "longtext", "clob", "string"},
+	},
+	{
+		{"binary"},
+		{"varbinary"},
+		{"tinyblob"},
+		{"blob", "mediumblob", "longblob", "bytea"},
+	},
+	{
+		{"timestamp", "timestamp without time zone", "datetime"},
+		{"timestamptz", "timestamp with time zone"},
+	},
+}
+
+func parseType(t string) (string, []int, bool) {
+	m := typeRe.FindStringSubmatch(strings.ToLower(t))
+	if m == nil {
+		return strings.ToLower(t), nil, false
+	}
+	params := []int{}
+	if m[2] != "" {
+		for _, p := range strings.Split(m[2], ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(p))
+			if err != nil {
+				return strings.ToLower(t), nil, false
+			}
+			params = append(params, n)
+		}
+	}
+	return m[1], params, m[3] != ""
+}
+
+func typeRank(base string) (int, int) {
+	for fi, family := range typeFamilies {
+		for ri, names := range family {
+			if lo.Contains(names, base) {
+				return fi, ri
+			}
+		}
+	}
+	return -1, -1
+}
+
+// compareTypes compares the column types and detect whether the change widens or narrows the type
+func compareTypes(from, to string) typeChange {
+	fb, fp, fu := parseType(from)
+	tb, tp, tu := parseType(to)
+	if fu != tu {
+		return typeIncompatible
+	}
+	if fb == tb {
+		if (fb == "numeric" || fb == "decimal") && len(fp) == 2 && len(tp) == 2 {
+			// compare the number of digits before and after the decimal point
+			return compareParams([]int{fp[0] - fp[1], fp[1]}, []int{tp[0] - tp[1], tp[1]})
+		}
+		return compareParams(fp, tp)
+	}
+	ff, fr := typeRank(fb)
+	tf, tr := typeRank(tb)
+	if ff < 0 || ff != tf {
+		return typeIncompatible
+	}
+	switch {
+	case fr < tr:
+		if len(tp) > 0 && compareParams(fp, tp) == typeNarrowed {
+			return typeNarrowed
+		}
+		return typeWidened
+	case fr > tr:
+		return typeNarrowed
+	}
+	return compareParams(fp, tp)
+}
+
+func compareParams(from, to []int) typeChange {
+	switch {
+	case len(from) == 0 && len(to) == 0:
+		return typeWidened
+	case len(from) == 0:
+		// e.g. varchar -> varchar(10)
+		return typeNarrowed
+	case len(to) == 0:
+		// e.g. varchar(10) -> varchar
+		return typeWidened
+	case len(from) != len(to):
+		return typeIncompatible
+	}
+	for i := range from {
+		if to[i] < from[i] {
+			return typeNarrowed
+		}
+	}
+	return typeWidened
+}
@@ -0,0 +1,184 @@
+package diff
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/k1LoW/tbls/schema"
+	"github.com/k1LoW/tbls/testutil"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *schema.Schema)
+		want   Severity
+	}{
+		{
+			"no changes",
+			func(s *schema.Schema) {},
+			SeverityNone,
+		},
+		{
+			"comment only",
+			func(s *schema.Schema) {
+				s.Tables[0].Comment = "changed"
+				s.Viewpoints = nil
+			},
+			SeverityNone,
+		},
+		{
+			"add nullable column",
+			func(s *schema.Schema) {
+				s.Tables[0].Columns = append(s.Tables[0].Columns, &schema.Column{Name: "a3", Type: "TEXT", Nullable: true})
+			},
+			SeverityAdditive,
+		},
+		{
+			"add not null column with default",
+			func(s *schema.Schema) {
+				s.Tables[0].Columns = append(s.Tables[0].Columns, &schema.Column{Name: "a3", Type: "TEXT", Default: sql.NullString{String: "''", Valid: true}})
+			},
+			SeverityAdditive,
+		},
+		{
+			"add not null column without default",
+			func(s *schema.Schema) {
+				s.Tables[0].Columns = append(s.Tables[0].Columns, &schema.Column{Name: "a3", Type: "TEXT"})
+			},
+			SeverityBreaking,
+		},
+		{
+			"drop column",
+			func(s *schema.Schema) {
+				s.Tables[1].Columns = s.Tables[1].Columns[:1]
+			},
+			SeverityBreaking,
+		},
+		{
+			"widen type",
+			func(s *schema.Schema) {
+				c, _ := s.Tables[0].FindColumnByName("a")
+				c.Type = "BIGINT"
+			},
+			SeverityAdditive,
+		},
+		{
+			"narrow type",
+			func(s *schema.Schema) {
+				c, _ := s.Tables[0].FindColumnByName("a2")
+				c.Type = "VARCHAR(10)"
+			},
+			SeverityBreaking,
+		},
+		{
+			"drop not null",
+			func(s *schema.Schema) {
+				s.Tables[0].Columns[1].Nullable = true
+			},
+			SeverityAdditive,
+		},
+		{
+			"drop primary key constraint",
+			func(s *schema.Schema) {
+				s.Tables[0].Constraints = nil
+			},
+			SeverityBreaking,
+		},
+		{
+			"drop trigger",
+			func(s *schema.Schema) {
+				s.Tables[0].Triggers = nil
+			},
+			SeverityAdditive,
+		},
+		{
+			"remove enum value",
+			func(s *schema.Schema) {
+				s.Enums[0].Values = []string{"one", "two"}
+			},
+			SeverityBreaking,
+		},
+		{
+			"add enum value",
+			func(s *schema.Schema) {
+				s.Enums[0].Values = append(s.Enums[0].Values, "four")
+			},
+			SeverityAdditive,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := testutil.NewSchema(t)
+			to := testutil.NewSchema(t)
+			tt.modify(to)
+			got := Compare(from, to).MaxSeverity()
+			if got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyNotNull(t *testing.T) {
+	from := testutil.NewSchema(t)
+	from.Tables[0].Columns[1].Nullable = true
+	to := testutil.NewSchema(t)
+	d := Compare(from, to)
+	if got := d.MaxSeverity(); got != SeverityBreaking {
+		t.Errorf("got %v\nwant %v", got, SeverityBreaking)
+	}
+	if got := d.Changes[0].Reason; got != "NOT NULL added without default" {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestCompareTypes(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want typeChange
+	}{
+		{"int", "bigint", typeWidened},
+		{"bigint", "int", typeNarrowed},
+		{"integer", "int4", typeWidened},
+		{"varchar(10)", "varchar(255)", typeWidened},
+		{"varchar(255)", "varchar(10)", typeNarrowed},
+		{"varchar(255)", "text", typeWidened},
+		{"text", "varchar(255)", typeNarrowed},
+		{"character varying", "character varying(10)", typeNarrowed},
+		{"numeric(10,2)", "numeric(12,2)", typeWidened},
+		{"numeric(10,2)", "numeric(10,4)", typeNarrowed},
+		{"int", "text", typeIncompatible},
+		{"int unsigned", "int", typeIncompatible},
+		{"timestamp", "timestamptz", typeWidened},
+	}
+	for _, tt := range tests {
+		got := compareTypes(tt.from, tt.to)
+		if got != tt.want {
+			t.Errorf("%s -> %s: got %v\nwant %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Severity
+		wantErr bool
+	}{
+		{"breaking", SeverityBreaking, false},
+		{"Additive", SeverityAdditive, false},
+		{"none", SeverityNone, false},
+		{"minor", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSeverity(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
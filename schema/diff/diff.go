@@ -0,0 +1,367 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/schema"
+	"github.com/samber/lo"
+)
+
+// Kind is the kind of schema object
+type Kind string
+
+const (
+	KindTable      Kind = "table"
+	KindColumn     Kind = "column"
+	KindIndex      Kind = "index"
+	KindConstraint Kind = "constraint"
+	KindTrigger    Kind = "trigger"
+	KindRelation   Kind = "relation"
+	KindFunction   Kind = "function"
+	KindEnum       Kind = "enum"
+	KindViewpoint  Kind = "viewpoint"
+)
+
+// Op is the operation applied to a schema object
+type Op string
+
+const (
+	OpAdded    Op = "added"
+	OpRemoved  Op = "removed"
+	OpModified Op = "modified"
+)
+
+// Diff is the structured difference between two schemas
+type Diff struct {
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	Changes []*Change `json:"changes"`
+
+	from *schema.Schema
+	to   *schema.Schema
+}
+
+// Change is the change of a schema object
+type Change struct {
+	Kind   Kind     `json:"kind"`
+	Op     Op       `json:"op"`
+	Table  string   `json:"table,omitempty"`
+	Name   string   `json:"name"`
+	Fields []*Field `json:"fields,omitempty"`
+
+	Severity Severity `json:"severity,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
+}
+
+// Field is the changed field of a modified schema object
+type Field struct {
+	Name string `json:"name"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Target return the name of the changed object including its table
+func (c *Change) Target() string {
+	if c.Table == "" || c.Kind == KindRelation {
+		return c.Name
+	}
+	return fmt.Sprintf("%s.%s", c.Table, c.Name)
+}
+
+// HasChanges return whether the diff has any change
+func (d *Diff) HasChanges() bool {
+	return len(d.Changes) > 0
+}
+
+// Compare compares two schemas object by object and classifies each change
+func Compare(from, to *schema.Schema) *Diff {
+	d := &Diff{
+		From:    from.Name,
+		To:      to.Name,
+		Changes: []*Change{},
+		from:    from,
+		to:      to,
+	}
+	d.compareTables(from.Tables, to.Tables)
+	d.compareRelations(from.Relations, to.Relations)
+	d.compareFunctions(from.Functions, to.Functions)
+	d.compareEnums(from.Enums, to.Enums)
+	d.compareViewpoints(from.Viewpoints, to.Viewpoints)
+	d.Classify()
+	return d
+}
+
+// WriteMarkdown write the diff in Markdown format
+func (d *Diff) WriteMarkdown(wr io.Writer) error {
+	r := strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+	b := new(strings.Builder)
+	_, _ = fmt.Fprintf(b, "# Diff `%s` `%s`\n\n", d.From, d.To)
+	if !d.HasChanges() {
+		b.WriteString("No changes.\n")
+		_, err := io.WriteString(wr, b.String())
+		return errors.WithStack(err)
+	}
+	b.WriteString("| Op | Kind | Name | Severity | Detail |\n")
+	b.WriteString("| -- | ---- | ---- | -------- | ------ |\n")
+	for _, c := range d.Changes {
+		details := []string{}
+		for _, f := range c.Fields {
+			details = append(details, fmt.Sprintf("%s: `%s` -> `%s`", f.Name, r.Replace(f.From), r.Replace(f.To)))
+		}
+		if c.Reason != "" {
+			details = append(details, r.Replace(c.Reason))
+		}
+		_, _ = fmt.Fprintf(b, "| %s | %s | `%s` | %s | %s |\n", c.Op, c.Kind, r.Replace(c.Target()), c.Severity, strings.Join(details, "<br>"))
+	}
+	_, err := io.WriteString(wr, b.String())
+	return errors.WithStack(err)
+}
+
+func (d *Diff) add(c *Change) {
+	d.Changes = append(d.Changes, c)
+}
+
+func (d *Diff) compareTables(from, to []*schema.Table) {
+	for _, ft := range from {
+		tt, ok := lo.Find(to, func(t *schema.Table) bool { return t.Name == ft.Name })
+		if !ok {
+			d.add(&Change{Kind: KindTable, Op: OpRemoved, Name: ft.Name})
+			continue
+		}
+		fields := []*Field{}
+		fields = appendField(fields, "type", ft.Type, tt.Type)
+		fields = appendField(fields, "comment", ft.Comment, tt.Comment)
+		fields = appendField(fields, "def", ft.Def, tt.Def)
+		fields = appendField(fields, "labels", labelsString(ft.Labels), labelsString(tt.Labels))
+		if len(fields) > 0 {
+			d.add(&Change{Kind: KindTable, Op: OpModified, Name: ft.Name, Fields: fields})
+		}
+		d.compareColumns(ft, tt)
+		d.compareIndexes(ft, tt)
+		d.compareConstraints(ft, tt)
+		d.compareTriggers(ft, tt)
+	}
+	for _, tt := range to {
+		if !lo.ContainsBy(from, func(t *schema.Table) bool { return t.Name == tt.Name }) {
+			d.add(&Change{Kind: KindTable, Op: OpAdded, Name: tt.Name})
+		}
+	}
+}
+
+func (d *Diff) compareColumns(ft, tt *schema.Table) {
+	for _, fc := range ft.Columns {
+		tc, ok := lo.Find(tt.Columns, func(c *schema.Column) bool { return c.Name == fc.Name })
+		if !ok {
+			d.add(&Change{Kind: KindColumn, Op: OpRemoved, Table: ft.Name, Name: fc.Name})
+			continue
+		}
+		fields := []*Field{}
+		fields = appendField(fields, "type", fc.Type, tc.Type)
+		fields = appendField(fields, "nullable", fmt.Sprintf("%t", fc.Nullable), fmt.Sprintf("%t", tc.Nullable))
+		fields = appendField(fields, "default", defaultString(fc), defaultString(tc))
+		fields = appendField(fields, "extra_def", fc.ExtraDef, tc.ExtraDef)
+		fields = appendField(fields, "comment", fc.Comment, tc.Comment)
+		fields = appendField(fields, "labels", labelsString(fc.Labels), labelsString(tc.Labels))
+		if len(fields) > 0 {
+			d.add(&Change{Kind: KindColumn, Op: OpModified, Table: ft.Name, Name: fc.Name, Fields: fields})
+		}
+	}
+	for _, tc := range tt.Columns {
+		if !lo.ContainsBy(ft.Columns, func(c *schema.Column) bool { return c.Name == tc.Name }) {
+			d.add(&Change{Kind: KindColumn, Op: OpAdded, Table: tt.Name, Name: tc.Name})
+		}
+	}
+}
+
+func (d *Diff) compareIndexes(ft, tt *schema.Table) {
+	for _, fi := range ft.Indexes {
+		ti, ok := lo.Find(tt.Indexes, func(i *schema.Index) bool { return i.Name == fi.Name })
+		if !ok {
+			d.add(&Change{Kind: KindIndex, Op: OpRemoved, Table: ft.Name, Name: fi.Name})
+			continue
+		}
+		fields := []*Field{}
+		fields = appendField(fields, "def", fi.Def, ti.Def)
+		fields = appendField(fields, "columns", strings.Join(fi.Columns, ", "), strings.Join(ti.Columns, ", "))
+		fields = appendField(fields, "comment", fi.Comment, ti.Comment)
+		if len(fields) > 0 {
+			d.add(&Change{Kind: KindIndex, Op: OpModified, Table: ft.Name, Name: fi.Name, Fields: fields})
+		}
+	}
+	for _, ti := range tt.Indexes {
+		if !lo.ContainsBy(ft.Indexes, func(i *schema.Index) bool { return i.Name == ti.Name }) {
+			d.add(&Change{Kind: KindIndex, Op: OpAdded, Table: tt.Name, Name: ti.Name})
+		}
+	}
+}
+
+func (d *Diff) compareConstraints(ft, tt *schema.Table) {
+	for _, fc := range ft.Constraints {
+		tc, ok := lo.Find(tt.Constraints, func(c *schema.Constraint) bool { return c.Name == fc.Name })
+		if !ok {
+			d.add(&Change{Kind: KindConstraint, Op: OpRemoved, Table: ft.Name, Name: fc.Name})
+			continue
+		}
+		fields := []*Field{}
+		fields = appendField(fields, "type", fc.Type, tc.Type)
+		fields = appendField(fields, "def", fc.Def, tc.Def)
+		fields = appendField(fields, "columns", strings.Join(fc.Columns, ", "), strings.Join(tc.Columns, ", "))
+		fields = appendField(fields, "referenced_table", lo.FromPtr(fc.ReferencedTable), lo.FromPtr(tc.ReferencedTable))
+		fields = appendField(fields, "referenced_columns", strings.Join(fc.ReferencedColumns, ", "), strings.Join(tc.ReferencedColumns, ", "))
+		fields = appendField(fields, "comment", fc.Comment, tc.Comment)
+		if len(fields) > 0 {
+			d.add(&Change{Kind: KindConstraint, Op: OpModified, Table: ft.Name, Name: fc.Name, Fields: fields})
+		}
+	}
+	for _, tc := range tt.Constraints {
+		if !lo.ContainsBy(ft.Constraints, func(c *schema.Constraint) bool { return c.Name == tc.Name }) {
+			d.add(&Change{Kind: KindConstraint, Op: OpAdded, Table: tt.Name, Name: tc.Name})
+		}
+	}
+}
+
+func (d *Diff) compareTriggers(ft, tt *schema.Table) {
+	for _, fg := range ft.Triggers {
+		tg, ok := lo.Find(tt.Triggers, func(g *schema.Trigger) bool { return g.Name == fg.Name })
+		if !ok {
+			d.add(&Change{Kind: KindTrigger, Op: OpRemoved, Table: ft.Name, Name: fg.Name})
+			continue
+		}
+		fields := []*Field{}
+		fields = appendField(fields, "def", fg.Def, tg.Def)
+		fields = appendField(fields, "comment", fg.Comment, tg.Comment)
+		if len(fields) > 0 {
+			d.add(&Change{Kind: KindTrigger, Op: OpModified, Table: ft.Name, Name: fg.Name, Fields: fields})
+		}
+	}
+	for _, tg := range tt.Triggers {
+		if !lo.ContainsBy(ft.Triggers, func(g *schema.Trigger) bool { return g.Name == tg.Name }) {
+			d.add(&Change{Kind: KindTrigger, Op: OpAdded, Table: tt.Name, Name: tg.Name})
+		}
+	}
+}
+
+func (d *Diff) compareRelations(from, to []*schema.Relation) {
+	for _, fr := range from {
+		tr, ok := lo.Find(to, func(r *schema.Relation) bool { return relationName(r) == relationName(fr) })
+		if !ok {
+			d.add(&Change{Kind: KindRelation, Op: OpRemoved, Table: fr.Table.Name, Name: relationName(fr)})
+			continue
+		}
+		fields := []*Field{}
+		fields = appendField(fields, "cardinality", fr.Cardinality.String(), tr.Cardinality.String())
+		fields = appendField(fields, "parent_cardinality", fr.ParentCardinality.String(), tr.ParentCardinality.String())
+		fields = appendField(fields, "def", fr.Def, tr.Def)
+		fields = appendField(fields, "virtual", fmt.Sprintf("%t", fr.Virtual), fmt.Sprintf("%t", tr.Virtual))
+		if len(fields) > 0 {
+			d.add(&Change{Kind: KindRelation, Op: OpModified, Table: fr.Table.Name, Name: relationName(fr), Fields: fields})
+		}
+	}
+	for _, tr := range to {
+		if !lo.ContainsBy(from, func(r *schema.Relation) bool { return relationName(r) == relationName(tr) }) {
+			d.add(&Change{Kind: KindRelation, Op: OpAdded, Table: tr.Table.Name, Name: relationName(tr)})
+		}
+	}
+}
+
+func (d *Diff) compareFunctions(from, to []*schema.Function) {
+	for _, ff := range from {
+		tf, ok := lo.Find(to, func(f *schema.Function) bool { return functionName(f) == functionName(ff) })
+		if !ok {
+			d.add(&Change{Kind: KindFunction, Op: OpRemoved, Name: functionName(ff)})
+			continue
+		}
+		fields := []*Field{}
+		fields = appendField(fields, "return_type", ff.ReturnType, tf.ReturnType)
+		fields = appendField(fields, "type", ff.Type, tf.Type)
+		if len(fields) > 0 {
+			d.add(&Change{Kind: KindFunction, Op: OpModified, Name: functionName(ff), Fields: fields})
+		}
+	}
+	for _, tf := range to {
+		if !lo.ContainsBy(from, func(f *schema.Function) bool { return functionName(f) == functionName(tf) }) {
+			d.add(&Change{Kind: KindFunction, Op: OpAdded, Name: functionName(tf)})
+		}
+	}
+}
+
+func (d *Diff) compareEnums(from, to []*schema.Enum) {
+	for _, fe := range from {
+		te, ok := lo.Find(to, func(e *schema.Enum) bool { return e.Name == fe.Name })
+		if !ok {
+			d.add(&Change{Kind: KindEnum, Op: OpRemoved, Name: fe.Name})
+			continue
+		}
+		fields := []*Field{}
+		fields = appendField(fields, "values", strings.Join(fe.Values, ", "), strings.Join(te.Values, ", "))
+		if len(fields) > 0 {
+			d.add(&Change{Kind: KindEnum, Op: OpModified, Name: fe.Name, Fields: fields})
+		}
+	}
+	for _, te := range to {
+		if !lo.ContainsBy(from, func(e *schema.Enum) bool { return e.Name == te.Name }) {
+			d.add(&Change{Kind: KindEnum, Op: OpAdded, Name: te.Name})
+		}
+	}
+}
+
+func (d *Diff) compareViewpoints(from, to schema.Viewpoints) {
+	for _, fv := range from {
+		tv, ok := lo.Find(to, func(v *schema.Viewpoint) bool { return v.Name == fv.Name })
+		if !ok {
+			d.add(&Change{Kind: KindViewpoint, Op: OpRemoved, Name: fv.Name})
+			continue
+		}
+		fields := []*Field{}
+		fields = appendField(fields, "desc", fv.Desc, tv.Desc)
+		fields = appendField(fields, "tables", strings.Join(fv.Tables, ", "), strings.Join(tv.Tables, ", "))
+		fields = appendField(fields, "labels", strings.Join(fv.Labels, ", "), strings.Join(tv.Labels, ", "))
+		fields = appendField(fields, "distance", fmt.Sprintf("%d", fv.Distance), fmt.Sprintf("%d", tv.Distance))
+		fields = appendField(fields, "groups", groupsString(fv.Groups), groupsString(tv.Groups))
+		if len(fields) > 0 {
+			d.add(&Change{Kind: KindViewpoint, Op: OpModified, Name: fv.Name, Fields: fields})
+		}
+	}
+	for _, tv := range to {
+		if !lo.ContainsBy(from, func(v *schema.Viewpoint) bool { return v.Name == tv.Name }) {
+			d.add(&Change{Kind: KindViewpoint, Op: OpAdded, Name: tv.Name})
+		}
+	}
+}
+
+func appendField(fields []*Field, name, from, to string) []*Field {
+	if from == to {
+		return fields
+	}
+	return append(fields, &Field{Name: name, From: from, To: to})
+}
+
+func defaultString(c *schema.Column) string {
+	if !c.Default.Valid {
+		return ""
+	}
+	return c.Default.String
+}
+
+func labelsString(labels schema.Labels) string {
+	return strings.Join(lo.Map(labels, func(l *schema.Label, _ int) string { return l.Name }), ", ")
+}
+
+func groupsString(groups []*schema.ViewpointGroup) string {
+	return strings.Join(lo.Map(groups, func(g *schema.ViewpointGroup, _ int) string { return g.Name }), ", ")
+}
+
+func columnNames(columns []*schema.Column) string {
+	return strings.Join(lo.Map(columns, func(c *schema.Column, _ int) string { return c.Name }), ", ")
+}
+
+func relationName(r *schema.Relation) string {
+	return fmt.Sprintf("%s(%s) -> %s(%s)", r.Table.Name, columnNames(r.Columns), r.ParentTable.Name, columnNames(r.ParentColumns))
+}
+
+func functionName(f *schema.Function) string {
+	return fmt.Sprintf("%s(%s)", f.Name, f.Arguments)
+}
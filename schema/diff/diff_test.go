@@ -0,0 +1,141 @@
+package diff
+
+import (
+	"bytes"
+	"database/sql"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/k1LoW/tbls/schema"
+	"github.com/k1LoW/tbls/testutil"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *schema.Schema)
+		want   []*Change
+	}{
+		{
+			"no changes",
+			func(s *schema.Schema) {},
+			[]*Change{},
+		},
+		{
+			"add table",
+			func(s *schema.Schema) {
+				s.Tables = append(s.Tables, &schema.Table{Name: "c", Type: "BASE TABLE"})
+			},
+			[]*Change{
+				{Kind: KindTable, Op: OpAdded, Name: "c"},
+			},
+		},
+		{
+			"remove table",
+			func(s *schema.Schema) {
+				s.Tables = s.Tables[:2]
+			},
+			[]*Change{
+				{Kind: KindTable, Op: OpRemoved, Name: "view"},
+			},
+		},
+		{
+			"modify column",
+			func(s *schema.Schema) {
+				c, _ := s.Tables[0].FindColumnByName("a2")
+				c.Type = "VARCHAR(10)"
+				c.Nullable = true
+				c.Default = sql.NullString{String: "'x'", Valid: true}
+			},
+			[]*Change{
+				{Kind: KindColumn, Op: OpModified, Table: "a", Name: "a2", Fields: []*Field{
+					{Name: "type", From: "TEXT", To: "VARCHAR(10)"},
+					{Name: "nullable", From: "false", To: "true"},
+					{Name: "default", From: "", To: "'x'"},
+				}},
+			},
+		},
+		{
+			"add and remove column",
+			func(s *schema.Schema) {
+				s.Tables[0].Columns = append(s.Tables[0].Columns[:1], &schema.Column{Name: "a3", Type: "TEXT"})
+			},
+			[]*Change{
+				{Kind: KindColumn, Op: OpRemoved, Table: "a", Name: "a2"},
+				{Kind: KindColumn, Op: OpAdded, Table: "a", Name: "a3"},
+			},
+		},
+		{
+			"remove index and trigger",
+			func(s *schema.Schema) {
+				s.Tables[0].Indexes = nil
+				s.Tables[0].Triggers = nil
+			},
+			[]*Change{
+				{Kind: KindIndex, Op: OpRemoved, Table: "a", Name: "PRIMARY KEY"},
+				{Kind: KindTrigger, Op: OpRemoved, Table: "a", Name: "update_a_a2"},
+			},
+		},
+		{
+			"modify relation",
+			func(s *schema.Schema) {
+				s.Relations[0].Cardinality = schema.ZeroOrMore
+			},
+			[]*Change{
+				{Kind: KindRelation, Op: OpModified, Table: "b", Name: "b(b) -> a(a)", Fields: []*Field{
+					{Name: "cardinality", From: "one_or_more", To: "zero_or_more"},
+				}},
+			},
+		},
+		{
+			"modify enum and remove viewpoint",
+			func(s *schema.Schema) {
+				s.Enums[0].Values = []string{"one", "two"}
+				s.Viewpoints = s.Viewpoints[1:]
+			},
+			[]*Change{
+				{Kind: KindEnum, Op: OpModified, Name: "enum", Fields: []*Field{
+					{Name: "values", From: "one, two, three", To: "one, two"},
+				}},
+				{Kind: KindViewpoint, Op: OpRemoved, Name: "table a b"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := testutil.NewSchema(t)
+			to := testutil.NewSchema(t)
+			tt.modify(to)
+			got := Compare(from, to)
+			if diff := cmp.Diff(got.Changes, tt.want, cmpopts.IgnoreFields(Change{}, "Severity", "Reason")); diff != "" {
+				t.Error(diff)
+			}
+			if got.HasChanges() != (len(tt.want) > 0) {
+				t.Errorf("got %v\nwant %v", got.HasChanges(), len(tt.want) > 0)
+			}
+		})
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	from := testutil.NewSchema(t)
+	to := testutil.NewSchema(t)
+	to.Tables[1].Comment = "table b|c"
+	to.Tables = to.Tables[1:]
+	to.Relations = nil
+	d := Compare(from, to)
+	got := new(bytes.Buffer)
+	if err := d.WriteMarkdown(got); err != nil {
+		t.Fatal(err)
+	}
+	want := "# Diff `testschema` `testschema`\n\n" +
+		"| Op | Kind | Name | Severity | Detail |\n" +
+		"| -- | ---- | ---- | -------- | ------ |\n" +
+		"| removed | table | `a` | breaking | table dropped; referenced by view |\n" +
+		"| modified | table | `b` | none | comment: `table b` -> `table b\\|c` |\n" +
+		"| removed | relation | `b(b) -> a(a)` | none |  |\n"
+	if diff := cmp.Diff(got.String(), want); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,186 @@
+package schema
+
+// tableGraph is the directed graph of tables. The edges are from child tables to parent tables.
+type tableGraph struct {
+	tables  []*Table
+	index   map[*Table]int
+	parents [][]int
+	self    []bool
+}
+
+func newTableGraph(s *Schema) *tableGraph {
+	g := &tableGraph{
+		tables:  s.Tables,
+		index:   map[*Table]int{},
+		parents: make([][]int, len(s.Tables)),
+		self:    make([]bool, len(s.Tables)),
+	}
+	for i, t := range s.Tables {
+		g.index[t] = i
+	}
+	added := map[[2]int]struct{}{}
+	for _, r := range s.Relations {
+		ci, ok := g.index[r.Table]
+		if !ok {
+			continue
+		}
+		pi, ok := g.index[r.ParentTable]
+		if !ok {
+			continue
+		}
+		if ci == pi {
+			g.self[ci] = true
+			continue
+		}
+		if _, ok := added[[2]int{ci, pi}]; ok {
+			continue
+		}
+		added[[2]int{ci, pi}] = struct{}{}
+		g.parents[ci] = append(g.parents[ci], pi)
+	}
+	return g
+}
+
+// components return strongly connected components by Tarjan's algorithm. The components are in reverse topological order ( parents first ).
+func (g *tableGraph) components() [][]int {
+	var (
+		idx     = 0
+		indexes = make([]int, len(g.tables))
+		lowlink = make([]int, len(g.tables))
+		onStack = make([]bool, len(g.tables))
+		stack   = []int{}
+		comps   = [][]int{}
+	)
+	for i := range indexes {
+		indexes[i] = -1
+	}
+	var connect func(v int)
+	connect = func(v int) {
+		indexes[v] = idx
+		lowlink[v] = idx
+		idx++
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, w := range g.parents[v] {
+			switch {
+			case indexes[w] < 0:
+				connect(w)
+				lowlink[v] = min(lowlink[v], lowlink[w])
+			case onStack[w]:
+				lowlink[v] = min(lowlink[v], indexes[w])
+			}
+		}
+		if lowlink[v] != indexes[v] {
+			return
+		}
+		comp := []int{}
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			comp = append(comp, w)
+			if w == v {
+				break
+			}
+		}
+		comps = append(comps, comp)
+	}
+	for v := range g.tables {
+		if indexes[v] < 0 {
+			connect(v)
+		}
+	}
+	return comps
+}
+
+func (g *tableGraph) toTables(comp []int) []*Table {
+	sorted := make([]bool, len(g.tables))
+	for _, i := range comp {
+		sorted[i] = true
+	}
+	tables := []*Table{}
+	for i, t := range g.tables {
+		if sorted[i] {
+			tables = append(tables, t)
+		}
+	}
+	return tables
+}
+
+// StronglyConnectedComponents return strongly connected components of tables linked by relations.
+// Tables in a component are in the order of Schema.Tables, and parent components come before child components.
+func (s *Schema) StronglyConnectedComponents() [][]*Table {
+	g := newTableGraph(s)
+	comps := [][]*Table{}
+	for _, comp := range g.components() {
+		comps = append(comps, g.toTables(comp))
+	}
+	return comps
+}
+
+// Cycles return groups of tables that reference each other by relations. A self-referencing table is also a cycle.
+func (s *Schema) Cycles() [][]*Table {
+	g := newTableGraph(s)
+	cycles := [][]*Table{}
+	for _, comp := range g.components() {
+		if len(comp) == 1 && !g.self[comp[0]] {
+			continue
+		}
+		cycles = append(cycles, g.toTables(comp))
+	}
+	return cycles
+}
+
+// TopologicalOrder return tables ordered so that parent tables come before child tables ( e.g. safe load order ).
+// Self-references are ignored, and tables in a cycle are placed together in the order of Schema.Tables.
+// Ties are broken by the order of Schema.Tables, so the result is deterministic.
+func (s *Schema) TopologicalOrder() []*Table {
+	g := newTableGraph(s)
+	comps := g.components()
+	compOf := make([]int, len(g.tables))
+	first := make([]int, len(comps))
+	for ci, comp := range comps {
+		first[ci] = len(g.tables)
+		for _, v := range comp {
+			compOf[v] = ci
+			first[ci] = min(first[ci], v)
+		}
+	}
+	// the number of unresolved parent components of each component
+	waiting := make([]int, len(comps))
+	children := make([][]int, len(comps))
+	linked := map[[2]int]struct{}{}
+	for v := range g.tables {
+		for _, w := range g.parents[v] {
+			c, p := compOf[v], compOf[w]
+			if c == p {
+				continue
+			}
+			if _, ok := linked[[2]int{c, p}]; ok {
+				continue
+			}
+			linked[[2]int{c, p}] = struct{}{}
+			waiting[c]++
+			children[p] = append(children[p], c)
+		}
+	}
+	done := make([]bool, len(comps))
+	ordered := []*Table{}
+	for range comps {
+		next := -1
+		for ci := range comps {
+			if done[ci] || waiting[ci] > 0 {
+				continue
+			}
+			if next < 0 || first[ci] < first[next] {
+				next = ci
+			}
+		}
+		done[next] = true
+		for _, c := range children[next] {
+			waiting[c]--
+		}
+		ordered = append(ordered, g.toTables(comps[next])...)
+	}
+	return ordered
+}
@@ -0,0 +1,90 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func newGraphTestSchema(names []string, rels [][2]string) *Schema {
+	s := &Schema{Name: "testschema"}
+	for _, n := range names {
+		s.Tables = append(s.Tables, &Table{Name: n})
+	}
+	for _, r := range rels {
+		c, _ := s.FindTableByName(r[0])
+		p, _ := s.FindTableByName(r[1])
+		s.Relations = append(s.Relations, &Relation{Table: c, ParentTable: p})
+	}
+	return s
+}
+
+func tableNames(tables []*Table) []string {
+	names := []string{}
+	for _, t := range tables {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func TestGraph(t *testing.T) {
+	tests := []struct {
+		name       string
+		tables     []string
+		relations  [][2]string // child, parent
+		wantOrder  []string
+		wantCycles [][]string
+		wantComps  int
+	}{
+		{
+			"no relations",
+			[]string{"c", "b", "a"},
+			nil,
+			[]string{"c", "b", "a"},
+			[][]string{},
+			3,
+		},
+		{
+			"chain",
+			[]string{"comments", "posts", "users"},
+			[][2]string{{"comments", "posts"}, {"posts", "users"}, {"comments", "users"}},
+			[]string{"users", "posts", "comments"},
+			[][]string{},
+			3,
+		},
+		{
+			"self reference",
+			[]string{"categories", "products"},
+			[][2]string{{"categories", "categories"}, {"products", "categories"}},
+			[]string{"categories", "products"},
+			[][]string{{"categories"}},
+			2,
+		},
+		{
+			"cycle",
+			[]string{"logs", "a", "b", "c", "root"},
+			[][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"a", "root"}, {"logs", "c"}},
+			[]string{"root", "a", "b", "c", "logs"},
+			[][]string{{"a", "b", "c"}},
+			3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newGraphTestSchema(tt.tables, tt.relations)
+			if diff := cmp.Diff(tableNames(s.TopologicalOrder()), tt.wantOrder); diff != "" {
+				t.Errorf("TopologicalOrder: %s", diff)
+			}
+			gotCycles := [][]string{}
+			for _, c := range s.Cycles() {
+				gotCycles = append(gotCycles, tableNames(c))
+			}
+			if diff := cmp.Diff(gotCycles, tt.wantCycles); diff != "" {
+				t.Errorf("Cycles: %s", diff)
+			}
+			if got := len(s.StronglyConnectedComponents()); got != tt.wantComps {
+				t.Errorf("StronglyConnectedComponents: got %v want %v", got, tt.wantComps)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Impact is the set of objects that depend on the target table or column
+type Impact struct {
+	Table       *Table
+	Column      *Column
+	Relations   []*ImpactRelation
+	Views       []*Table
+	Triggers    []*Trigger
+	Indexes     []*Index
+	Constraints []*Constraint
+	Viewpoints  []*Viewpoint
+}
+
+// ImpactRelation is the child relation that depends on the target. Distance is the number of relations from the target.
+type ImpactRelation struct {
+	Relation *Relation
+	Distance int
+}
+
+// ImpactJSON is a JSON representation of Impact
+type ImpactJSON struct {
+	Target      string                `json:"target"`
+	Relations   []*ImpactRelationJSON `json:"relations"`
+	Views       []string              `json:"views"`
+	Triggers    []string              `json:"triggers"`
+	Indexes     []string              `json:"indexes"`
+	Constraints []string              `json:"constraints"`
+	Viewpoints  []string              `json:"viewpoints"`
+}
+
+// ImpactRelationJSON is a JSON representation of ImpactRelation
+type ImpactRelationJSON struct {
+	Table         string   `json:"table"`
+	Columns       []string `json:"columns"`
+	ParentTable   string   `json:"parent_table"`
+	ParentColumns []string `json:"parent_columns"`
+	Distance      int      `json:"distance"`
+}
+
+// Impact return the objects that depend on the table t. If c is not nil, return the objects that depend on the column c of the table t.
+// Child relations are collected transitively.
+func (s *Schema) Impact(t *Table, c *Column) *Impact {
+	i := &Impact{
+		Table:       t,
+		Column:      c,
+		Relations:   s.impactRelations(t, c),
+		Views:       []*Table{},
+		Triggers:    t.Triggers,
+		Indexes:     []*Index{},
+		Constraints: []*Constraint{},
+		Viewpoints:  []*Viewpoint{},
+	}
+	if i.Triggers == nil {
+		i.Triggers = []*Trigger{}
+	}
+	for _, v := range s.Tables {
+		if slices.Contains(v.ReferencedTables, t) || slices.ContainsFunc(v.ReferencedTables, func(rt *Table) bool {
+			return s.NormalizeTableName(rt.Name) == s.NormalizeTableName(t.Name)
+		}) {
+			i.Views = append(i.Views, v)
+		}
+	}
+	for _, idx := range t.Indexes {
+		if c == nil || slices.Contains(idx.Columns, c.Name) {
+			i.Indexes = append(i.Indexes, idx)
+		}
+	}
+	for _, tt := range s.Tables {
+		for _, cst := range tt.Constraints {
+			own := tt == t && (c == nil || slices.Contains(cst.Columns, c.Name))
+			referencing := tt != t && cst.ReferencedTable != nil && s.NormalizeTableName(*cst.ReferencedTable) == s.NormalizeTableName(t.Name) &&
+				(c == nil || slices.Contains(cst.ReferencedColumns, c.Name))
+			if own || referencing {
+				i.Constraints = append(i.Constraints, cst)
+			}
+		}
+	}
+	for _, v := range s.Viewpoints {
+		if s.viewpointContains(v, t) {
+			i.Viewpoints = append(i.Viewpoints, v)
+		}
+	}
+	return i
+}
+
+// Target return the name of the target
+func (i *Impact) Target() string {
+	if i.Column == nil {
+		return i.Table.Name
+	}
+	return fmt.Sprintf("%s.%s", i.Table.Name, i.Column.Name)
+}
+
+// ToJSONObject convert Impact to ImpactJSON
+func (i *Impact) ToJSONObject() ImpactJSON {
+	j := ImpactJSON{
+		Target:      i.Target(),
+		Relations:   []*ImpactRelationJSON{},
+		Views:       []string{},
+		Triggers:    []string{},
+		Indexes:     []string{},
+		Constraints: []string{},
+		Viewpoints:  []string{},
+	}
+	for _, r := range i.Relations {
+		j.Relations = append(j.Relations, &ImpactRelationJSON{
+			Table:         r.Relation.Table.Name,
+			Columns:       columnNames(r.Relation.Columns),
+			ParentTable:   r.Relation.ParentTable.Name,
+			ParentColumns: columnNames(r.Relation.ParentColumns),
+			Distance:      r.Distance,
+		})
+	}
+	for _, v := range i.Views {
+		j.Views = append(j.Views, v.Name)
+	}
+	for _, tr := range i.Triggers {
+		j.Triggers = append(j.Triggers, tr.Name)
+	}
+	for _, idx := range i.Indexes {
+		j.Indexes = append(j.Indexes, idx.Name)
+	}
+	for _, cst := range i.Constraints {
+		j.Constraints = append(j.Constraints, cst.Name)
+	}
+	for _, v := range i.Viewpoints {
+		j.Viewpoints = append(j.Viewpoints, v.Name)
+	}
+	return j
+}
+
+// String return the relation like `posts.user_id -> users.id`
+func (r *ImpactRelation) String() string {
+	return fmt.Sprintf("%s -> %s", qualifiedColumns(r.Relation.Table, r.Relation.Columns), qualifiedColumns(r.Relation.ParentTable, r.Relation.ParentColumns))
+}
+
+// impactRelations collect child relations by breadth-first search.
+// For a table, all child relations of the descendant tables are collected.
+// For a column, only child relations referencing the column ( and the child columns transitively ) are collected.
+func (s *Schema) impactRelations(t *Table, c *Column) []*ImpactRelation {
+	rels := []*ImpactRelation{}
+	seen := map[*Relation]struct{}{}
+	if c != nil {
+		visited := map[*Column]struct{}{c: {}}
+		frontier := []*Column{c}
+		for d := 1; len(frontier) > 0; d++ {
+			next := []*Column{}
+			for _, fc := range frontier {
+				for _, r := range fc.ChildRelations {
+					if _, ok := seen[r]; ok {
+						continue
+					}
+					seen[r] = struct{}{}
+					rels = append(rels, &ImpactRelation{Relation: r, Distance: d})
+					for _, cc := range r.Columns {
+						if _, ok := visited[cc]; ok {
+							continue
+						}
+						visited[cc] = struct{}{}
+						next = append(next, cc)
+					}
+				}
+			}
+			frontier = next
+		}
+		return rels
+	}
+	visited := map[*Table]struct{}{t: {}}
+	frontier := []*Table{t}
+	for d := 1; len(frontier) > 0; d++ {
+		next := []*Table{}
+		for _, ft := range frontier {
+			for _, r := range s.Relations {
+				if r.ParentTable != ft {
+					continue
+				}
+				if _, ok := seen[r]; ok {
+					continue
+				}
+				seen[r] = struct{}{}
+				rels = append(rels, &ImpactRelation{Relation: r, Distance: d})
+				if _, ok := visited[r.Table]; ok {
+					continue
+				}
+				visited[r.Table] = struct{}{}
+				next = append(next, r.Table)
+			}
+		}
+		frontier = next
+	}
+	return rels
+}
+
+// viewpointContains return true if the schema of the viewpoint ( the same as the document of the viewpoint ) contains the table
+func (s *Schema) viewpointContains(v *Viewpoint, t *Table) bool {
+	vs := v.Schema
+	if vs == nil {
+		var err error
+		vs, err = s.viewpointSchema(v)
+		if err != nil {
+			return false
+		}
+	}
+	_, err := vs.FindTableByName(t.Name)
+	return err == nil
+}
+
+func columnNames(cs []*Column) []string {
+	names := []string{}
+	for _, c := range cs {
+		names = append(names, c.Name)
+	}
+	return names
+}
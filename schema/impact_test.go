@@ -0,0 +1,123 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestImpact(t *testing.T) {
+	s := newPathTestSchema(t)
+	regions, _ := s.FindTableByName("regions")
+	customers, _ := s.FindTableByName("customers")
+	orders, _ := s.FindTableByName("orders")
+	regionsName := "regions"
+	customersName := "customers"
+	regions.Indexes = []*Index{
+		{Name: "regions_pkey", Table: &regionsName, Columns: []string{"id"}},
+		{Name: "regions_parent_id_idx", Table: &regionsName, Columns: []string{"parent_id"}},
+	}
+	regions.Constraints = []*Constraint{
+		{Name: "regions_pkey", Type: "PRIMARY KEY", Table: &regionsName, Columns: []string{"id"}},
+	}
+	regions.Triggers = []*Trigger{{Name: "update_regions_updated"}}
+	customers.Constraints = []*Constraint{
+		{Name: "customers_region_id_fkey", Type: "FOREIGN KEY", Table: &customersName, Columns: []string{"region_id"}, ReferencedTable: &regionsName, ReferencedColumns: []string{"id"}},
+	}
+	orders.Labels = Labels{{Name: "sales"}}
+	view := &Table{Name: "region_summary", Type: "VIEW", ReferencedTables: []*Table{{Name: "regions"}}}
+	s.Tables = append(s.Tables, view)
+	s.Viewpoints = Viewpoints{
+		// customers is included by distance:
+		{Name: "geo", Tables: []string{"regions"}, Distance: 1},
+		{Name: "sales", Labels: []string{"sales"}},
+		{Name: "all", Tables: []string{"*"}},
+	}
+	if err := s.Repair(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		table  string
+		column string
+		want   ImpactJSON
+	}{
+		{
+			"regions",
+			"",
+			ImpactJSON{
+				Target: "regions",
+				Relations: []*ImpactRelationJSON{
+					{Table: "customers", Columns: []string{"region_id"}, ParentTable: "regions", ParentColumns: []string{"id"}, Distance: 1},
+					{Table: "stores", Columns: []string{"region_id"}, ParentTable: "regions", ParentColumns: []string{"id"}, Distance: 1},
+					{Table: "regions", Columns: []string{"parent_id"}, ParentTable: "regions", ParentColumns: []string{"id"}, Distance: 1},
+					{Table: "orders", Columns: []string{"customer_id"}, ParentTable: "customers", ParentColumns: []string{"id"}, Distance: 2},
+					{Table: "orders", Columns: []string{"store_id"}, ParentTable: "stores", ParentColumns: []string{"id"}, Distance: 2},
+				},
+				Views:       []string{"region_summary"},
+				Triggers:    []string{"update_regions_updated"},
+				Indexes:     []string{"regions_pkey", "regions_parent_id_idx"},
+				Constraints: []string{"customers_region_id_fkey", "regions_pkey"},
+				Viewpoints:  []string{"geo", "all"},
+			},
+		},
+		{
+			"regions",
+			"parent_id",
+			ImpactJSON{
+				Target:      "regions.parent_id",
+				Relations:   []*ImpactRelationJSON{},
+				Views:       []string{"region_summary"},
+				Triggers:    []string{"update_regions_updated"},
+				Indexes:     []string{"regions_parent_id_idx"},
+				Constraints: []string{},
+				Viewpoints:  []string{"geo", "all"},
+			},
+		},
+		{
+			"customers",
+			"id",
+			ImpactJSON{
+				Target: "customers.id",
+				Relations: []*ImpactRelationJSON{
+					{Table: "orders", Columns: []string{"customer_id"}, ParentTable: "customers", ParentColumns: []string{"id"}, Distance: 1},
+				},
+				Views:       []string{},
+				Triggers:    []string{},
+				Indexes:     []string{},
+				Constraints: []string{},
+				Viewpoints:  []string{"geo", "all"},
+			},
+		},
+		{
+			"orders",
+			"",
+			ImpactJSON{
+				Target:      "orders",
+				Relations:   []*ImpactRelationJSON{},
+				Views:       []string{},
+				Triggers:    []string{},
+				Indexes:     []string{},
+				Constraints: []string{},
+				Viewpoints:  []string{"sales", "all"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tbl, err := s.FindTableByName(tt.table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var c *Column
+		if tt.column != "" {
+			c, err = tbl.FindColumnByName(tt.column)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		got := s.Impact(tbl, c).ToJSONObject()
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Errorf("%s: %s", tt.want.Target, diff)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// JoinStep is the step of JoinPath that joins To table to From table by Relation
+type JoinStep struct {
+	From     *Table
+	To       *Table
+	Relation *Relation
+}
+
+// JoinPath is the path of relations between tables
+type JoinPath []*JoinStep
+
+// FromColumns return the columns of From table used by the join
+func (st *JoinStep) FromColumns() []*Column {
+	if st.Relation.Table == st.From {
+		return st.Relation.Columns
+	}
+	return st.Relation.ParentColumns
+}
+
+// ToColumns return the columns of To table used by the join
+func (st *JoinStep) ToColumns() []*Column {
+	if st.Relation.Table == st.From {
+		return st.Relation.ParentColumns
+	}
+	return st.Relation.Columns
+}
+
+// Tables return the tables on the path in order
+func (p JoinPath) Tables() []*Table {
+	if len(p) == 0 {
+		return []*Table{}
+	}
+	tables := []*Table{p[0].From}
+	for _, st := range p {
+		tables = append(tables, st.To)
+	}
+	return tables
+}
+
+// String return the step like `orders.customer_id -> customers.id`
+func (st *JoinStep) String() string {
+	return fmt.Sprintf("%s -> %s", qualifiedColumns(st.From, st.FromColumns()), qualifiedColumns(st.To, st.ToColumns()))
+}
+
+// String return the path like `orders.customer_id -> customers.id, customers.region_id -> regions.id`
+func (p JoinPath) String() string {
+	steps := []string{}
+	for _, st := range p {
+		steps = append(steps, st.String())
+	}
+	return strings.Join(steps, ", ")
+}
+
+// SelectSQL return the skeleton of SELECT statement joining the tables on the path
+func (p JoinPath) SelectSQL() string {
+	if len(p) == 0 {
+		return ""
+	}
+	lines := []string{"SELECT", "  *", fmt.Sprintf("FROM %s", p[0].From.Name)}
+	for _, st := range p {
+		conds := []string{}
+		fcs, tcs := st.FromColumns(), st.ToColumns()
+		for i := range min(len(fcs), len(tcs)) {
+			conds = append(conds, fmt.Sprintf("%s.%s = %s.%s", st.To.Name, tcs[i].Name, st.From.Name, fcs[i].Name))
+		}
+		lines = append(lines, fmt.Sprintf("  JOIN %s ON %s", st.To.Name, strings.Join(conds, " AND ")))
+	}
+	return strings.Join(lines, "\n") + ";"
+}
+
+// ShortestJoinPaths return all shortest paths from table `from` to table `to` through relations of columns.
+// Relations are followed in both directions ( child to parent and parent to child ).
+// If limit > 0, at most limit paths are returned.
+func (s *Schema) ShortestJoinPaths(from, to *Table, limit int) []JoinPath {
+	distFrom := joinDistances(from)
+	if _, ok := distFrom[to]; !ok {
+		return []JoinPath{}
+	}
+	distTo := joinDistances(to)
+	paths := []JoinPath{}
+	var walk func(t *Table, path JoinPath) bool
+	walk = func(t *Table, path JoinPath) bool {
+		if t == to {
+			paths = append(paths, append(JoinPath{}, path...))
+			return limit <= 0 || len(paths) < limit
+		}
+		for _, st := range joinSteps(t) {
+			if distFrom[st.To] != distFrom[t]+1 || distTo[st.To] != distTo[t]-1 {
+				continue
+			}
+			if !walk(st.To, append(path, st)) {
+				return false
+			}
+		}
+		return true
+	}
+	walk(from, JoinPath{})
+	return paths
+}
+
+// joinDistances return the number of joins from table t to reachable tables by breadth-first search
+func joinDistances(t *Table) map[*Table]int {
+	dist := map[*Table]int{t: 0}
+	queue := []*Table{t}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, st := range joinSteps(cur) {
+			if _, ok := dist[st.To]; ok {
+				continue
+			}
+			dist[st.To] = dist[cur] + 1
+			queue = append(queue, st.To)
+		}
+	}
+	return dist
+}
+
+// joinSteps return the steps from table t using ParentRelations and ChildRelations of the columns
+func joinSteps(t *Table) []*JoinStep {
+	steps := []*JoinStep{}
+	added := map[*Relation]struct{}{}
+	for _, c := range t.Columns {
+		for _, r := range c.ParentRelations {
+			if _, ok := added[r]; ok || r.ParentTable == nil || r.ParentTable == t {
+				continue
+			}
+			added[r] = struct{}{}
+			steps = append(steps, &JoinStep{From: t, To: r.ParentTable, Relation: r})
+		}
+		for _, r := range c.ChildRelations {
+			if _, ok := added[r]; ok || r.Table == nil || r.Table == t {
+				continue
+			}
+			added[r] = struct{}{}
+			steps = append(steps, &JoinStep{From: t, To: r.Table, Relation: r})
+		}
+	}
+	return steps
+}
+
+func qualifiedColumns(t *Table, cs []*Column) string {
+	names := columnNames(cs)
+	if len(names) == 1 {
+		return fmt.Sprintf("%s.%s", t.Name, names[0])
+	}
+	return fmt.Sprintf("%s.(%s)", t.Name, strings.Join(names, ", "))
+}
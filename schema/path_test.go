@@ -0,0 +1,98 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func newPathTestSchema(t *testing.T) *Schema {
+	t.Helper()
+	s := &Schema{Name: "testschema"}
+	for _, tc := range [][]string{
+		{"orders", "id", "customer_id", "store_id"},
+		{"customers", "id", "region_id"},
+		{"stores", "id", "region_id"},
+		{"regions", "id", "parent_id"},
+		{"logs", "id"},
+	} {
+		tbl := &Table{Name: tc[0]}
+		for _, c := range tc[1:] {
+			tbl.Columns = append(tbl.Columns, &Column{Name: c})
+		}
+		s.Tables = append(s.Tables, tbl)
+	}
+	for _, rc := range [][4]string{
+		{"orders", "customer_id", "customers", "id"},
+		{"orders", "store_id", "stores", "id"},
+		{"customers", "region_id", "regions", "id"},
+		{"stores", "region_id", "regions", "id"},
+		{"regions", "parent_id", "regions", "id"},
+	} {
+		ct, _ := s.FindTableByName(rc[0])
+		cc, _ := ct.FindColumnByName(rc[1])
+		pt, _ := s.FindTableByName(rc[2])
+		pc, _ := pt.FindColumnByName(rc[3])
+		r := &Relation{Table: ct, Columns: []*Column{cc}, ParentTable: pt, ParentColumns: []*Column{pc}}
+		cc.ParentRelations = append(cc.ParentRelations, r)
+		pc.ChildRelations = append(pc.ChildRelations, r)
+		s.Relations = append(s.Relations, r)
+	}
+	return s
+}
+
+func TestShortestJoinPaths(t *testing.T) {
+	tests := []struct {
+		from  string
+		to    string
+		limit int
+		want  []string
+	}{
+		{"orders", "regions", 0, []string{
+			"orders.customer_id -> customers.id, customers.region_id -> regions.id",
+			"orders.store_id -> stores.id, stores.region_id -> regions.id",
+		}},
+		{"orders", "regions", 1, []string{
+			"orders.customer_id -> customers.id, customers.region_id -> regions.id",
+		}},
+		{"regions", "customers", 0, []string{
+			"regions.id -> customers.region_id",
+		}},
+		{"customers", "stores", 0, []string{
+			"customers.id -> orders.customer_id, orders.store_id -> stores.id",
+			"customers.region_id -> regions.id, regions.id -> stores.region_id",
+		}},
+		{"orders", "logs", 0, []string{}},
+		{"orders", "orders", 0, []string{""}},
+	}
+	s := newPathTestSchema(t)
+	for _, tt := range tests {
+		from, _ := s.FindTableByName(tt.from)
+		to, _ := s.FindTableByName(tt.to)
+		got := []string{}
+		for _, p := range s.ShortestJoinPaths(from, to, tt.limit) {
+			got = append(got, p.String())
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Errorf("%s -> %s: %s", tt.from, tt.to, diff)
+		}
+	}
+}
+
+func TestJoinPathSelectSQL(t *testing.T) {
+	s := newPathTestSchema(t)
+	from, _ := s.FindTableByName("orders")
+	to, _ := s.FindTableByName("regions")
+	paths := s.ShortestJoinPaths(from, to, 1)
+	want := `SELECT
+  *
+FROM orders
+  JOIN customers ON customers.id = orders.customer_id
+  JOIN regions ON regions.id = customers.region_id;`
+	if got := paths[0].SelectSQL(); got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if diff := cmp.Diff(tableNames(paths[0].Tables()), []string{"orders", "customers", "regions"}); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -348,22 +348,31 @@ func (s *Schema) Repair() (err error) {
 	}
 	// viewpoints should be created using as complete a schema as possible
 	for _, v := range s.Viewpoints {
-		cs, err := s.CloneWithoutViewpoints()
+		cs, err := s.viewpointSchema(v)
 		if err != nil {
 			return fmt.Errorf("failed to repair viewpoint: %w", err)
 		}
-		if err := cs.Filter(&FilterOption{
-			Include:       v.Tables,
-			IncludeLabels: v.Labels,
-			Distance:      v.Distance,
-		}); err != nil {
-			return fmt.Errorf("failed to repair viewpoint: %w", err)
-		}
 		v.Schema = cs
 	}
 	return nil
 }
 
+// viewpointSchema return the schema filtered by the viewpoint ( tables:, labels: and distance: )
+func (s *Schema) viewpointSchema(v *Viewpoint) (*Schema, error) {
+	cs, err := s.CloneWithoutViewpoints()
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.Filter(&FilterOption{
+		Include:       v.Tables,
+		IncludeLabels: v.Labels,
+		Distance:      v.Distance,
+	}); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
 func (s *Schema) Clone() (c *Schema, err error) {
 	defer func() {
 		err = errors.WithStack(err)
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/config"
+	"github.com/k1LoW/tbls/output/gviz"
+	"github.com/k1LoW/tbls/schema"
+)
+
+// TableSummary is the summary of table returned by GET /tables
+type TableSummary struct {
+	Name    string        `json:"name"`
+	Type    string        `json:"type"`
+	Comment string        `json:"comment,omitempty"`
+	Labels  schema.Labels `json:"labels,omitempty"`
+}
+
+// SearchResult is the table or column matched by GET /search
+type SearchResult struct {
+	Table   string `json:"table"`
+	Column  string `json:"column,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
+func (s *Server) handleAPI(mux *http.ServeMux) {
+	mux.HandleFunc("GET /tables", s.withSchema(func(w http.ResponseWriter, r *http.Request, sc *schema.Schema) {
+		tables := sc.Tables
+		if name := r.URL.Query().Get("name"); name != "" {
+			var err error
+			tables, err = sc.MatchTablesByName(name)
+			if err != nil {
+				tables = []*schema.Table{}
+			}
+		}
+		summaries := []TableSummary{}
+		for _, t := range tables {
+			summaries = append(summaries, TableSummary{Name: t.Name, Type: t.Type, Comment: t.Comment, Labels: t.Labels})
+		}
+		writeJSON(w, http.StatusOK, summaries)
+	}))
+	mux.HandleFunc("GET /tables/{name}", s.withTable(func(w http.ResponseWriter, r *http.Request, t *schema.Table) {
+		writeJSON(w, http.StatusOK, t)
+	}))
+	mux.HandleFunc("GET /tables/{name}/columns", s.withTable(func(w http.ResponseWriter, r *http.Request, t *schema.Table) {
+		columns := t.Columns
+		if columns == nil {
+			columns = []*schema.Column{}
+		}
+		writeJSON(w, http.StatusOK, columns)
+	}))
+	mux.HandleFunc("GET /tables/{name}/columns/{column}", s.withTable(func(w http.ResponseWriter, r *http.Request, t *schema.Table) {
+		c, err := t.FindColumnByName(r.PathValue("column"))
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, c)
+	}))
+	mux.HandleFunc("GET /relations", s.withSchema(func(w http.ResponseWriter, r *http.Request, sc *schema.Schema) {
+		relations := []*schema.Relation{}
+		table := r.URL.Query().Get("table")
+		for _, rel := range sc.Relations {
+			if table != "" && sc.NormalizeTableName(rel.Table.Name) != sc.NormalizeTableName(table) && sc.NormalizeTableName(rel.ParentTable.Name) != sc.NormalizeTableName(table) {
+				continue
+			}
+			relations = append(relations, rel)
+		}
+		writeJSON(w, http.StatusOK, relations)
+	}))
+	mux.HandleFunc("GET /viewpoints", s.withSchema(func(w http.ResponseWriter, r *http.Request, sc *schema.Schema) {
+		viewpoints := sc.Viewpoints
+		if viewpoints == nil {
+			viewpoints = schema.Viewpoints{}
+		}
+		writeJSON(w, http.StatusOK, viewpoints)
+	}))
+	mux.HandleFunc("GET /search", s.withSchema(func(w http.ResponseWriter, r *http.Request, sc *schema.Schema) {
+		q := r.URL.Query().Get("q")
+		if q == "" {
+			writeError(w, http.StatusBadRequest, errors.New("query parameter 'q' is required"))
+			return
+		}
+		writeJSON(w, http.StatusOK, search(sc, q))
+	}))
+	// wildcards match whole segments, so {table}.svg is matched as {file}
+	mux.HandleFunc("GET /er/{file}", func(w http.ResponseWriter, r *http.Request) {
+		name, ok := strings.CutSuffix(r.PathValue("file"), ".svg")
+		if !ok {
+			writeError(w, http.StatusNotFound, errors.New("only svg is supported"))
+			return
+		}
+		b, err := s.cached("er/"+name, func(c *config.Config, sc *schema.Schema) ([]byte, error) {
+			t, err := sc.FindTableByName(name)
+			if err != nil {
+				return nil, errNotFound
+			}
+			buf := new(bytes.Buffer)
+			if err := gviz.NewWithFormat(c, "svg").OutputTable(buf, t); err != nil {
+				return nil, err
+			}
+			return buf.Bytes(), nil
+		})
+		if err != nil {
+			if errors.Is(err, errNotFound) {
+				writeError(w, http.StatusNotFound, err)
+				return
+			}
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		w.Header().Set("Content-Type", "image/svg+xml")
+		_, _ = w.Write(b)
+	})
+}
+
+func (s *Server) withSchema(f func(w http.ResponseWriter, r *http.Request, sc *schema.Schema)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, sc := s.current()
+		if sc == nil {
+			writeError(w, http.StatusServiceUnavailable, errors.New("schema is not analyzed yet"))
+			return
+		}
+		f(w, r, sc)
+	}
+}
+
+func (s *Server) withTable(f func(w http.ResponseWriter, r *http.Request, t *schema.Table)) http.HandlerFunc {
+	return s.withSchema(func(w http.ResponseWriter, r *http.Request, sc *schema.Schema) {
+		t, err := sc.FindTableByName(r.PathValue("name"))
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		f(w, r, t)
+	})
+}
+
+// search tables and columns whose name or comment contains q ( case-insensitive )
+func search(sc *schema.Schema, q string) []SearchResult {
+	q = strings.ToLower(q)
+	contains := func(v string) bool {
+		return strings.Contains(strings.ToLower(v), q)
+	}
+	results := []SearchResult{}
+	for _, t := range sc.Tables {
+		if contains(t.Name) || contains(t.Comment) {
+			results = append(results, SearchResult{Table: t.Name, Comment: t.Comment})
+		}
+		for _, c := range t.Columns {
+			if contains(c.Name) || contains(c.Comment) {
+				results = append(results, SearchResult{Table: t.Name, Column: c.Name, Comment: c.Comment})
+			}
+		}
+	}
+	return results
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/tbls/config"
+)
+
+func TestAPI(t *testing.T) {
+	s := New(writeConfig(t, "blog"), []config.Option{config.ERSkip(true)})
+	if err := s.Load(); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		pick       func(v any) any
+		want       any
+	}{
+		{"/tables", http.StatusOK, names("name"), []any{"users", "posts", "comments"}},
+		{"/tables?name=p*", http.StatusOK, names("name"), []any{"posts"}},
+		{"/tables?name=missing", http.StatusOK, names("name"), []any{}},
+		{"/tables/posts", http.StatusOK, field("comment"), "Posts table"},
+		{"/tables/missing", http.StatusNotFound, field("error"), "not found table 'missing'"},
+		{"/tables/users/columns", http.StatusOK, names("name"), []any{"id", "username", "email", "created"}},
+		{"/tables/users/columns/username", http.StatusOK, field("comment"), "user name"},
+		{"/tables/users/columns/missing", http.StatusNotFound, field("error"), "not found column 'missing' on table 'users'"},
+		{"/relations", http.StatusOK, names("table"), []any{"posts", "comments", "comments"}},
+		{"/relations?table=posts", http.StatusOK, names("table"), []any{"posts", "comments"}},
+		{"/viewpoints", http.StatusOK, names("name"), []any{"content"}},
+		{"/search?q=USER", http.StatusOK, names("column"), []any{nil, "username", "user_id", "user_id"}},
+		{"/search", http.StatusBadRequest, field("error"), "query parameter 'q' is required"},
+		{"/er/users.png", http.StatusNotFound, field("error"), "only svg is supported"},
+		{"/er/missing.svg", http.StatusNotFound, field("error"), "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			res, err := http.Get(ts.URL + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+			if res.StatusCode != tt.wantStatus {
+				t.Errorf("got %v\nwant %v", res.StatusCode, tt.wantStatus)
+			}
+			var v any
+			if err := json.NewDecoder(res.Body).Decode(&v); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.pick(v), tt.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func field(key string) func(v any) any {
+	return func(v any) any {
+		return v.(map[string]any)[key]
+	}
+}
+
+func names(key string) func(v any) any {
+	return func(v any) any {
+		got := []any{}
+		for _, e := range v.([]any) {
+			got = append(got, e.(map[string]any)[key])
+		}
+		return got
+	}
+}
@@ -0,0 +1,298 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/k1LoW/errors"
+	"github.com/k1LoW/tbls/config"
+	"github.com/k1LoW/tbls/datasource"
+	"github.com/k1LoW/tbls/output/html"
+	"github.com/k1LoW/tbls/schema"
+)
+
+// DefaultCheckInterval is the interval to check changes of the config file
+const DefaultCheckInterval = time.Second
+
+const generationPath = "/_tbls/generation"
+
+// liveReloadScript reloads the page when the schema is re-analyzed
+const liveReloadScript = `<script>
+(function () {
+  var generation = null;
+  setInterval(function () {
+    fetch('` + generationPath + `').then(function (res) { return res.text(); }).then(function (g) {
+      if (generation !== null && generation !== g) { location.reload(); }
+      generation = g;
+    }).catch(function () {});
+  }, 2000);
+})();
+</script>
+`
+
+var viewpointPageRe = regexp.MustCompile(`^viewpoint-(\d+)\.html$`)
+
+// Server serves the document of the database analyzed on demand
+type Server struct {
+	configPath    string
+	options       []config.Option
+	interval      time.Duration
+	checkInterval time.Duration
+
+	mu            sync.RWMutex
+	config        *config.Config
+	schema        *schema.Schema
+	generation    int
+	analyzedAt    time.Time
+	configModTime time.Time
+	pages         map[string][]byte
+}
+
+// Option is the function to set the options of Server
+type Option func(*Server)
+
+// Interval set the interval to re-analyze the database. 0 means re-analyzing only when the config file changes
+func Interval(d time.Duration) Option {
+	return func(s *Server) {
+		s.interval = d
+	}
+}
+
+// CheckInterval set the interval to check changes of the config file
+func CheckInterval(d time.Duration) Option {
+	return func(s *Server) {
+		s.checkInterval = d
+	}
+}
+
+// New return Server. configPath and options are passed to config.Config.Load on every analysis.
+func New(configPath string, options []config.Option, opts ...Option) *Server {
+	s := &Server{
+		configPath:    configPath,
+		options:       options,
+		checkInterval: DefaultCheckInterval,
+		pages:         map[string][]byte{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Load config and analyze the database
+func (s *Server) Load() error {
+	c, err := config.New()
+	if err != nil {
+		return err
+	}
+	if err := c.Load(s.configPath, s.options...); err != nil {
+		return err
+	}
+	sc, err := datasource.Analyze(c.DSN)
+	if err != nil {
+		return err
+	}
+	if err := c.ModifySchema(sc); err != nil {
+		return err
+	}
+	modTime := time.Time{}
+	if c.Path != "" {
+		if fi, err := os.Stat(c.Path); err == nil {
+			modTime = fi.ModTime()
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config = c
+	s.schema = sc
+	s.generation++
+	s.analyzedAt = time.Now()
+	s.configModTime = modTime
+	s.pages = map[string][]byte{}
+	return nil
+}
+
+// Generation return the number of times the database has been analyzed
+func (s *Server) Generation() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.generation
+}
+
+// Watch re-analyze the database on the interval or when the config file changes until ctx is done.
+// Errors of re-analyzing are reported to errWr and the previous result continues to be served.
+func (s *Server) Watch(ctx context.Context, errWr io.Writer) {
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.needsReload() {
+				continue
+			}
+			if err := s.Load(); err != nil {
+				_, _ = fmt.Fprintf(errWr, "failed to re-analyze: %s\n", err)
+				s.mu.Lock()
+				// retry on the next interval, not on every check
+				s.analyzedAt = time.Now()
+				if s.config != nil && s.config.Path != "" {
+					if fi, err := os.Stat(s.config.Path); err == nil {
+						s.configModTime = fi.ModTime()
+					}
+				}
+				s.mu.Unlock()
+			}
+		}
+	}
+}
+
+// needsReload return true if the interval has passed or the config file has changed
+func (s *Server) needsReload() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.interval > 0 && time.Since(s.analyzedAt) >= s.interval {
+		return true
+	}
+	if s.config == nil || s.config.Path == "" {
+		return false
+	}
+	fi, err := os.Stat(s.config.Path)
+	if err != nil {
+		return false
+	}
+	return !fi.ModTime().Equal(s.configModTime)
+}
+
+// Handler return http.Handler serving the document
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+generationPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = fmt.Fprintf(w, "%d", s.Generation())
+	})
+	s.handleAPI(mux)
+	mux.HandleFunc("GET /{file}", s.handleFile)
+	mux.HandleFunc("GET /{$}", s.handleFile)
+	return mux
+}
+
+func (s *Server) handleFile(w http.ResponseWriter, r *http.Request) {
+	file := r.PathValue("file")
+	if file == "" {
+		file = "index.html"
+	}
+	if b, err := html.Asset(file); err == nil {
+		w.Header().Set("Content-Type", contentType(file))
+		_, _ = w.Write(b)
+		return
+	}
+	b, err := s.page(file)
+	if err != nil {
+		if errors.Is(err, errNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType(file))
+	_, _ = w.Write(b)
+}
+
+var errNotFound = errors.New("not found")
+
+// page render the page. Rendered pages ( including ER diagrams ) are cached until the next analysis.
+func (s *Server) page(file string) ([]byte, error) {
+	return s.cached(file, func(c *config.Config, sc *schema.Schema) ([]byte, error) {
+		h := html.New(c)
+		buf := new(bytes.Buffer)
+		switch {
+		case file == "index.html":
+			if err := h.OutputSchema(buf, sc); err != nil {
+				return nil, err
+			}
+		case file == "search-index.js":
+			if err := html.OutputSearchIndex(buf, sc); err != nil {
+				return nil, err
+			}
+		case viewpointPageRe.MatchString(file):
+			i, err := strconv.Atoi(viewpointPageRe.FindStringSubmatch(file)[1])
+			if err != nil || i >= len(sc.Viewpoints) {
+				return nil, errNotFound
+			}
+			if err := h.OutputViewpoint(buf, i, sc.Viewpoints[i]); err != nil {
+				return nil, err
+			}
+		case strings.HasSuffix(file, ".html"):
+			t, err := sc.FindTableByName(strings.TrimSuffix(file, ".html"))
+			if err != nil {
+				return nil, errNotFound
+			}
+			if err := h.OutputTable(buf, t); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, errNotFound
+		}
+		b := buf.Bytes()
+		if strings.HasSuffix(file, ".html") {
+			b = bytes.Replace(b, []byte("</body>"), []byte(liveReloadScript+"</body>"), 1)
+		}
+		return b, nil
+	})
+}
+
+// cached return the cached result of render for the current analysis
+func (s *Server) cached(key string, render func(c *config.Config, sc *schema.Schema) ([]byte, error)) ([]byte, error) {
+	s.mu.RLock()
+	c, sc, generation := s.config, s.schema, s.generation
+	b, ok := s.pages[key]
+	s.mu.RUnlock()
+	if ok {
+		return b, nil
+	}
+	if sc == nil {
+		return nil, errNotFound
+	}
+	b, err := render(c, sc)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	if s.generation == generation {
+		s.pages[key] = b
+	}
+	s.mu.Unlock()
+	return b, nil
+}
+
+// current return the config and the schema of the current analysis
+func (s *Server) current() (*config.Config, *schema.Schema) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config, s.schema
+}
+
+func contentType(file string) string {
+	switch {
+	case strings.HasSuffix(file, ".html"):
+		return "text/html; charset=utf-8"
+	case strings.HasSuffix(file, ".css"):
+		return "text/css; charset=utf-8"
+	case strings.HasSuffix(file, ".js"):
+		return "text/javascript; charset=utf-8"
+	default:
+		return "application/octet-stream"
+	}
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/tbls/config"
+)
+
+func TestHandler(t *testing.T) {
+	s := New(writeConfig(t, "blog"), []config.Option{config.ERSkip(true)})
+	if err := s.Load(); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	tests := []struct {
+		path        string
+		wantStatus  int
+		wantContain string
+	}{
+		{"/", http.StatusOK, "<title>blog</title>"},
+		{"/index.html", http.StatusOK, "posts.html"},
+		{"/posts.html", http.StatusOK, "<title>posts</title>"},
+		{"/posts.html", http.StatusOK, "/_tbls/generation"},
+		{"/style.css", http.StatusOK, "body"},
+		{"/search-index.js", http.StatusOK, "window.tblsSearchIndex"},
+		{"/_tbls/generation", http.StatusOK, "1"},
+		{"/missing.html", http.StatusNotFound, ""},
+		{"/viewpoint-99.html", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			res, err := http.Get(ts.URL + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+			if res.StatusCode != tt.wantStatus {
+				t.Errorf("got %v\nwant %v", res.StatusCode, tt.wantStatus)
+			}
+			b, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(b), tt.wantContain) {
+				t.Errorf("got %s\nwant to contain %s", b, tt.wantContain)
+			}
+		})
+	}
+}
+
+func TestWatch(t *testing.T) {
+	path := writeConfig(t, "blog")
+	s := New(path, []config.Option{config.ERSkip(true)}, CheckInterval(10*time.Millisecond))
+	if err := s.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if s.needsReload() {
+		t.Error("want no reload before the config file changes")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Watch(ctx, io.Discard)
+
+	writeConfigTo(t, path, "blog2")
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for s.Generation() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("timeout waiting for re-analyzing")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	b, err := s.page("index.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "<title>blog2</title>") {
+		t.Errorf("want re-analyzed schema name blog2: %s", b)
+	}
+}
+
+func TestInterval(t *testing.T) {
+	s := New(writeConfig(t, "blog"), []config.Option{config.ERSkip(true)}, Interval(time.Millisecond))
+	if err := s.Load(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if !s.needsReload() {
+		t.Error("want reload after the interval")
+	}
+}
+
+func writeConfig(t *testing.T, name string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".tbls.yml")
+	writeConfigTo(t, path, name)
+	return path
+}
+
+func writeConfigTo(t *testing.T, path, name string) {
+	t.Helper()
+	dsn := fmt.Sprintf("dbml://%s", filepath.Join(testdataDir(), "dbml", "blog.dbml"))
+	if err := os.WriteFile(path, []byte(fmt.Sprintf("name: %s\ndsn: %s\n", name, dsn)), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testdataDir() string {
+	wd, _ := os.Getwd()
+	dir, _ := filepath.Abs(filepath.Join(filepath.Dir(wd), "testdata"))
+	return dir
+}
@@ -0,0 +1,7 @@
+CREATE TABLE users (
+  id serial PRIMARY KEY,
+  username varchar (50) UNIQUE NOT NULL,
+  email varchar (355) UNIQUE NOT NULL,
+  created timestamp NOT NULL
+);
+COMMENT ON TABLE users IS 'Users table';
@@ -0,0 +1,9 @@
+CREATE TABLE posts (
+  id bigserial NOT NULL,
+  user_id int NOT NULL,
+  title varchar (255) NOT NULL,
+  body text NOT NULL,
+  CONSTRAINT posts_id_pk PRIMARY KEY(id),
+  CONSTRAINT posts_user_id_fk FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX posts_user_id_idx ON posts USING btree (user_id);
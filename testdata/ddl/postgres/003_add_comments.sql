@@ -0,0 +1,7 @@
+CREATE TABLE comments (
+  id bigserial PRIMARY KEY,
+  post_id bigint NOT NULL REFERENCES posts(id),
+  user_id int NOT NULL REFERENCES users(id),
+  comment text NOT NULL
+);
+ALTER TABLE posts ADD COLUMN updated timestamp;
@@ -0,0 +1 @@
+CREATE VIEW post_titles AS SELECT p.id, u.username, p.title, p.body FROM posts p JOIN users u ON u.id = p.user_id;
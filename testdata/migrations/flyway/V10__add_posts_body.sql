@@ -0,0 +1,3 @@
+-- V10 must be applied after V2
+ALTER TABLE posts ADD COLUMN body TEXT;
+CREATE INDEX posts_user_id_idx ON posts(user_id);
@@ -0,0 +1,8 @@
+-- +goose Up
+CREATE TABLE users (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  username TEXT UNIQUE NOT NULL
+);
+
+-- +goose Down
+DROP TABLE users;
@@ -0,0 +1,12 @@
+-- create comments
+-- comments on posts
+
+-- +goose Up
+CREATE TABLE comments (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  post_id INTEGER NOT NULL REFERENCES posts(id),
+  body TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE comments;